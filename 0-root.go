@@ -1,6 +1,7 @@
 package prayer
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -9,6 +10,11 @@ type Schedule struct {
 	// Date is the ISO date, useful for logging.
 	Date string
 
+	// Hijri is the Hijri date of the day, using the calendar and day offset that
+	// specified in the configuration. It's only set if `IncludeHijri` is enabled or
+	// `RamadanCorrections` is specified.
+	Hijri HijriDate
+
 	// Fajr is the time when the sky begins to lighten (dawn) after previously
 	// completely dark.
 	Fajr time.Time
@@ -40,6 +46,76 @@ type Schedule struct {
 	IsNormal bool
 }
 
+// Prayer is identifier for each time (prayer and related events) in a schedule.
+type Prayer int
+
+const (
+	Fajr Prayer = iota + 1
+	Sunrise
+	Zuhr
+	Asr
+	Maghrib
+	Isha
+)
+
+// String returns the name of the prayer.
+func (p Prayer) String() string {
+	switch p {
+	case Fajr:
+		return "Fajr"
+	case Sunrise:
+		return "Sunrise"
+	case Zuhr:
+		return "Zuhr"
+	case Asr:
+		return "Asr"
+	case Maghrib:
+		return "Maghrib"
+	case Isha:
+		return "Isha"
+	default:
+		return ""
+	}
+}
+
+// Time returns the time of the specified prayer in the schedule.
+func (s Schedule) Time(p Prayer) time.Time {
+	switch p {
+	case Fajr:
+		return s.Fajr
+	case Sunrise:
+		return s.Sunrise
+	case Zuhr:
+		return s.Zuhr
+	case Asr:
+		return s.Asr
+	case Maghrib:
+		return s.Maghrib
+	case Isha:
+		return s.Isha
+	default:
+		return time.Time{}
+	}
+}
+
+// setTime sets the time of the specified prayer in the schedule.
+func (s *Schedule) setTime(p Prayer, t time.Time) {
+	switch p {
+	case Fajr:
+		s.Fajr = t
+	case Sunrise:
+		s.Sunrise = t
+	case Zuhr:
+		s.Zuhr = t
+	case Asr:
+		s.Asr = t
+	case Maghrib:
+		s.Maghrib = t
+	case Isha:
+		s.Isha = t
+	}
+}
+
 // ScheduleCorrections is correction for each prayer time.
 type ScheduleCorrections struct {
 	Fajr    time.Duration
@@ -81,9 +157,14 @@ type Config struct {
 	Elevation float64
 
 	// Timezone is the time zone of the location specified above. If not specified,
-	// it will use UTC.
+	// it will be resolved using `TimezoneLookup`, or use UTC if there are no lookup.
 	Timezone *time.Location
 
+	// TimezoneLookup is the function for resolving the time zone from the location
+	// when `Timezone` is not specified, e.g. `timezone.Lookup` from the offline
+	// timezone subpackage. It's used as the "auto" time zone mode.
+	TimezoneLookup func(latitude, longitude float64) (*time.Location, error)
+
 	// TwilightConvention is the convention that used to specify time for Fajr and
 	// Isha. By default it will use `AstronomicalTwilight`.
 	TwilightConvention *TwilightConvention
@@ -92,69 +173,207 @@ type Config struct {
 	// two conventions, Shafii and Hanafi. By default it will use Shafii.
 	AsrConvention AsrConvention
 
-	// HighLatitudeAdapter is the function for adjusting prayer times in area with
+	// HighLatitudeAdapter is the adapter for adjusting prayer times in area with
 	// high latitude (>=45 degrees). If not specified, it will not calculate the
 	// adjustment for higher latitude and instead will return the schedule as it is.
 	// For area in high or extreme latitude, it might return zero for Fajr, Sunrise,
 	// Maghrib and Isha.
 	HighLatitudeAdapter HighLatitudeAdapter
 
+	// EstimateMissingAsr specify whether Asr that still missing after adjusted by
+	// `HighLatitudeAdapter` (which happens in latitude around 85 degrees or more) will
+	// be estimated using its proportion between Zuhr and Maghrib in the nearest days.
+	// Since most adapters only adjust Fajr and Isha, enable it if Asr must always
+	// exist. It's ignored if there are no adapter.
+	EstimateMissingAsr bool
+
 	// Corrections is used to corrects calculated time for each specified prayer.
 	Corrections ScheduleCorrections
 
-	// PreciseToSeconds specify whether output time will omit the seconds or not.
+	// DailyCorrections is used to corrects calculated time for each specified prayer
+	// with corrections that vary by date, e.g. using `CorrectionTable` or
+	// `MonthlyCorrections`. It will be applied on top of `Corrections`.
+	DailyCorrections CorrectionFunc
+
+	// RamadanCorrections is used to corrects calculated time for each specified
+	// prayer during Ramadan, e.g. to extend Isha. The month is decided using
+	// `HijriCalendar` and `HijriOffset`. It will be applied on top of `Corrections`
+	// and `DailyCorrections`.
+	RamadanCorrections ScheduleCorrections
+
+	// PreciseToSeconds specify whether output time will omit the seconds or not. It's
+	// a shortcut for rounding policy: if false, every times will be rounded to the
+	// nearest minute. This field is ignored if `Rounding` is specified.
 	PreciseToSeconds bool
+
+	// Rounding is the rounding policy for each prayer time. If specified, it will be
+	// used instead of `PreciseToSeconds`.
+	Rounding *RoundingPolicy
+
+	// HijriCalendar is the calendar system that used for the Hijri date in each
+	// schedule. By default it will use `HijriTabular`.
+	HijriCalendar HijriCalendar
+
+	// HijriOffset is the number of days that added to the Hijri date, to match
+	// the local moon sighting announcement. For example, use -1 if the local
+	// authority starts the month one day later than the calendar.
+	HijriOffset int
+
+	// IncludeHijri specify whether the Hijri date will be set in each schedule. It's
+	// disabled by default since some calendars are expensive to calculate. However,
+	// the Hijri date is always set if `RamadanCorrections` is specified.
+	IncludeHijri bool
 }
 
 // Calculate calculates the prayer time for the entire year with specified configuration.
 func Calculate(cfg Config, year int) ([]Schedule, error) {
 	// Apply default config
-	if cfg.Timezone == nil {
-		cfg.Timezone = time.UTC
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate the schedules
+	_, schedules := calcAdjusted(cfg, year)
+
+	// Final check
+	finalizer := newScheduleFinalizer(cfg)
+	for i, s := range schedules {
+		date := time.Date(year, 1, i+1, 0, 0, 0, 0, cfg.Timezone)
+		schedules[i], err = finalizer.finalize(s, date)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return schedules, nil
+}
+
+// withDefaults returns the config with its default values applied.
+func (cfg Config) withDefaults() (Config, error) {
+	tz, err := cfg.location()
+	if err != nil {
+		return cfg, err
 	}
+	cfg.Timezone = tz
 
 	if cfg.TwilightConvention == nil {
 		cfg.TwilightConvention = AstronomicalTwilight()
 	}
 
-	// Calculate the schedules
+	if cfg.Rounding == nil {
+		if cfg.PreciseToSeconds {
+			cfg.Rounding = UniformRounding(RoundNone, 0)
+		} else {
+			cfg.Rounding = UniformRounding(RoundNearest, time.Minute)
+		}
+	}
+
+	return cfg, nil
+}
+
+// calcAdjusted calculates the schedules for the entire year, then adjusts them using
+// the high latitude adapter. It returns both the schedules before and after adjusted.
+// The config must be already applied with its default values.
+func calcAdjusted(cfg Config, year int) (raw, adjusted []Schedule) {
 	schedules, nAbnormal := calcNormal(cfg, year)
+	if nAbnormal == 0 || cfg.HighLatitudeAdapter == nil {
+		return schedules, schedules
+	}
 
-	// Apply high latitude adapter
-	if nAbnormal > 0 && cfg.HighLatitudeAdapter != nil {
-		schedules = cfg.HighLatitudeAdapter(cfg, year, schedules)
+	// Apply high latitude adapter, then estimate Asr if needed since most adapters
+	// only adjust Fajr and Isha.
+	raw = copySchedules(schedules)
+	adjusted = cfg.HighLatitudeAdapter(cfg, year, schedules)
+	if cfg.EstimateMissingAsr {
+		adjusted = fillMissingAsr(adjusted)
 	}
+	return raw, adjusted
+}
 
-	// Final check
-	fixedMaghribDuration := cfg.TwilightConvention.MaghribDuration
-	for i, s := range schedules {
-		// Apply Isha times for convention where Isha time is fixed after Maghrib
-		if fixedMaghribDuration > 0 {
-			s.Isha = s.Maghrib.Add(fixedMaghribDuration)
-		}
+// scheduleFinalizer applies the fixed Isha, Hijri date, corrections and rounding to
+// the adjusted schedules.
+type scheduleFinalizer struct {
+	cfg                   Config
+	hijri                 *hijriConverter
+	hasRamadanCorrections bool
+}
+
+func newScheduleFinalizer(cfg Config) *scheduleFinalizer {
+	sf := &scheduleFinalizer{
+		cfg:                   cfg,
+		hasRamadanCorrections: cfg.RamadanCorrections != (ScheduleCorrections{}),
+	}
+
+	// Hijri date is only calculated if it's needed
+	if cfg.IncludeHijri || sf.hasRamadanCorrections {
+		sf.hijri = newHijriConverter(cfg)
+	}
+
+	return sf
+}
+
+func (sf *scheduleFinalizer) finalize(s Schedule, date time.Time) (Schedule, error) {
+	cfg := sf.cfg
 
-		// Apply time correction
-		s.Fajr = applyCorrection(s.Fajr, cfg.Corrections.Fajr)
-		s.Sunrise = applyCorrection(s.Sunrise, cfg.Corrections.Sunrise)
-		s.Zuhr = applyCorrection(s.Zuhr, cfg.Corrections.Zuhr)
-		s.Asr = applyCorrection(s.Asr, cfg.Corrections.Asr)
-		s.Maghrib = applyCorrection(s.Maghrib, cfg.Corrections.Maghrib)
-		s.Isha = applyCorrection(s.Isha, cfg.Corrections.Isha)
-
-		// If needed round the time to minute
-		if !cfg.PreciseToSeconds {
-			s.Fajr = s.Fajr.Round(time.Minute)
-			s.Sunrise = s.Sunrise.Round(time.Minute)
-			s.Zuhr = s.Zuhr.Round(time.Minute)
-			s.Asr = s.Asr.Round(time.Minute)
-			s.Maghrib = s.Maghrib.Round(time.Minute)
-			s.Isha = s.Isha.Round(time.Minute)
+	// Apply Isha times for convention where Isha time is fixed after Maghrib
+	if fixedMaghribDuration := cfg.TwilightConvention.MaghribDuration; fixedMaghribDuration > 0 {
+		s.Isha = s.Maghrib.Add(fixedMaghribDuration)
+	}
+
+	// Set the Hijri date if needed
+	if sf.hijri != nil {
+		var err error
+		s.Hijri, err = sf.hijri.convert(date)
+		if err != nil {
+			return Schedule{}, err
 		}
+	}
 
-		schedules[i] = s
+	// Apply time correction
+	corrections := cfg.Corrections
+	if cfg.DailyCorrections != nil {
+		corrections = corrections.add(cfg.DailyCorrections(s.Zuhr))
 	}
 
-	return schedules, nil
+	if sf.hasRamadanCorrections && s.Hijri.Month == 9 {
+		corrections = corrections.add(cfg.RamadanCorrections)
+	}
+
+	s.Fajr = applyCorrection(s.Fajr, corrections.Fajr)
+	s.Sunrise = applyCorrection(s.Sunrise, corrections.Sunrise)
+	s.Zuhr = applyCorrection(s.Zuhr, corrections.Zuhr)
+	s.Asr = applyCorrection(s.Asr, corrections.Asr)
+	s.Maghrib = applyCorrection(s.Maghrib, corrections.Maghrib)
+	s.Isha = applyCorrection(s.Isha, corrections.Isha)
+
+	// Apply rounding
+	rounding := cfg.Rounding
+	s.Fajr = applyRounding(s.Fajr, rounding.Fajr)
+	s.Sunrise = applyRounding(s.Sunrise, rounding.Sunrise)
+	s.Zuhr = applyRounding(s.Zuhr, rounding.Zuhr)
+	s.Asr = applyRounding(s.Asr, rounding.Asr)
+	s.Maghrib = applyRounding(s.Maghrib, rounding.Maghrib)
+	s.Isha = applyRounding(s.Isha, rounding.Isha)
+
+	return s, nil
+}
+
+// location returns the time zone of the config, resolving it from the coordinate if
+// needed.
+func (cfg Config) location() (*time.Location, error) {
+	switch {
+	case cfg.Timezone != nil:
+		return cfg.Timezone, nil
+	case cfg.TimezoneLookup != nil:
+		tz, err := cfg.TimezoneLookup(cfg.Latitude, cfg.Longitude)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve timezone: %w", err)
+		}
+		return tz, nil
+	default:
+		return time.UTC, nil
+	}
 }
 
 func applyCorrection(t time.Time, d time.Duration) time.Time {
@@ -0,0 +1,105 @@
+package prayer
+
+import "time"
+
+// RegionalPresets is collection of full configuration that based on the published
+// methodology of official authorities, including the twilight convention, the safety
+// minutes (ihtiyat), the Zuhr adjustment, the rounding, the Asr convention and the
+// high latitude adapter. Use it through `Presets` variable.
+//
+// The local offices might still adjust their timetables manually, so if it matters,
+// compare the result with the timetable of your local authority and adjust the
+// `Corrections` when needed.
+type RegionalPresets struct{}
+
+// Presets is the collection of official regional presets, e.g.
+// `prayer.Presets.Kemenag(lat, lon, tz)`.
+var Presets RegionalPresets
+
+// Kemenag returns configuration based on the methodology of Kementerian Agama
+// Republik Indonesia: Fajr at 20° and Isha at 18°, Shafii Asr, and 2 minutes ihtiyat
+// added to each prayer and subtracted from sunrise. Zuhr is also delayed by 1 minute
+// for the Sun's disc to entirely pass the meridian. The times are then rounded up to
+// the minute, except sunrise which rounded down.
+func (RegionalPresets) Kemenag(latitude, longitude float64, tz *time.Location) Config {
+	return southeastAsiaPreset(latitude, longitude, tz, Kemenag())
+}
+
+// JAKIM returns configuration based on the methodology of Jabatan Kemajuan Islam
+// Malaysia: Fajr at 20° and Isha at 18°, Shafii Asr, and 2 minutes ihtiyat added to
+// each prayer and subtracted from sunrise. Zuhr is also delayed by 1 minute for the
+// Sun's disc to entirely pass the meridian. The times are then rounded up to the
+// minute, except sunrise which rounded down.
+func (RegionalPresets) JAKIM(latitude, longitude float64, tz *time.Location) Config {
+	return southeastAsiaPreset(latitude, longitude, tz, JAKIM())
+}
+
+// MUIS returns configuration based on the methodology of Majlis Ugama Islam
+// Singapura: Fajr at 20° and Isha at 18°, Shafii Asr, and 2 minutes ihtiyat added to
+// each prayer and subtracted from sunrise. Zuhr is also delayed by 1 minute for the
+// Sun's disc to entirely pass the meridian. The times are then rounded up to the
+// minute, except sunrise which rounded down.
+func (RegionalPresets) MUIS(latitude, longitude float64, tz *time.Location) Config {
+	return southeastAsiaPreset(latitude, longitude, tz, MUIS())
+}
+
+// zuhrDiscDelay is the delay of Zuhr after transit, which is the approximate time for
+// the Sun's disc (semi-diameter around 16 arcminutes) to entirely pass the meridian.
+const zuhrDiscDelay = time.Minute
+
+func southeastAsiaPreset(latitude, longitude float64, tz *time.Location, tc *TwilightConvention) Config {
+	corrections := ihtiyatCorrections(2 * time.Minute)
+	corrections.Zuhr += zuhrDiscDelay
+
+	return Config{
+		Latitude:           latitude,
+		Longitude:          longitude,
+		Timezone:           tz,
+		TwilightConvention: tc,
+		AsrConvention:      Shafii,
+		Corrections:        corrections,
+		Rounding:           SafeRounding(time.Minute),
+	}
+}
+
+// Diyanet returns configuration based on the methodology of Turkey's Diyanet
+// İşleri Başkanlığı: Fajr at 18° and Isha at 17°, Shafii Asr (asr-ı evvel), with
+// temkin minutes from `DiyanetTemkin` and high latitude handling from
+// `DiyanetHighLatitude`.
+func (RegionalPresets) Diyanet(latitude, longitude float64, tz *time.Location) Config {
+	return Config{
+		Latitude:            latitude,
+		Longitude:           longitude,
+		Timezone:            tz,
+		TwilightConvention:  Diyanet(),
+		AsrConvention:       Shafii,
+		HighLatitudeAdapter: DiyanetHighLatitude(),
+		Corrections:         DiyanetTemkin(),
+	}
+}
+
+// UmmAlQura returns configuration based on the methodology of Umm al-Qura
+// University in Makkah: Fajr at 18.5°, Isha 90 minutes after Maghrib which extended
+// to 120 minutes during Ramadan according to Umm al-Qura calendar, and Shafii Asr.
+func (RegionalPresets) UmmAlQura(latitude, longitude float64, tz *time.Location) Config {
+	return Config{
+		Latitude:           latitude,
+		Longitude:          longitude,
+		Timezone:           tz,
+		TwilightConvention: UmmAlQura(),
+		AsrConvention:      Shafii,
+		RamadanCorrections: ScheduleCorrections{Isha: 30 * time.Minute},
+		HijriCalendar:      HijriUmmAlQura,
+	}
+}
+
+func ihtiyatCorrections(d time.Duration) ScheduleCorrections {
+	return ScheduleCorrections{
+		Fajr:    d,
+		Sunrise: -d,
+		Zuhr:    d,
+		Asr:     d,
+		Maghrib: d,
+		Isha:    d,
+	}
+}
@@ -0,0 +1,62 @@
+package prayer_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+	"github.com/hablullah/go-prayer/internal/datatest"
+)
+
+func TestPresets(t *testing.T) {
+	td := datatest.Jakarta
+	precises, _ := prayer.Calculate(prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.Kemenag(),
+		PreciseToSeconds:   true,
+	}, 2023)
+
+	// Kemenag adds 2 minutes ihtiyat (plus 1 minute for Zuhr) then rounds it safely
+	schedules, _ := prayer.Calculate(prayer.Presets.Kemenag(td.Latitude, td.Longitude, td.Timezone), 2023)
+	for i, s := range schedules {
+		p := precises[i]
+		assertPresetDiff(t, "Kemenag", s.Date, "Fajr", s.Fajr.Sub(p.Fajr), 2*time.Minute, 3*time.Minute)
+		assertPresetDiff(t, "Kemenag", s.Date, "Sunrise", s.Sunrise.Sub(p.Sunrise), -3*time.Minute, -2*time.Minute)
+		assertPresetDiff(t, "Kemenag", s.Date, "Zuhr", s.Zuhr.Sub(p.Zuhr), 3*time.Minute, 4*time.Minute)
+		assertPresetDiff(t, "Kemenag", s.Date, "Asr", s.Asr.Sub(p.Asr), 2*time.Minute, 3*time.Minute)
+		assertPresetDiff(t, "Kemenag", s.Date, "Maghrib", s.Maghrib.Sub(p.Maghrib), 2*time.Minute, 3*time.Minute)
+		assertPresetDiff(t, "Kemenag", s.Date, "Isha", s.Isha.Sub(p.Isha), 2*time.Minute, 3*time.Minute)
+	}
+
+	// Umm al-Qura extends Isha to 120 minutes after Maghrib in Ramadan
+	schedules, _ = prayer.Calculate(prayer.Presets.UmmAlQura(td.Latitude, td.Longitude, td.Timezone), 2023)
+	for _, s := range schedules {
+		expected := 90 * time.Minute
+		if s.Date >= "2023-03-23" && s.Date <= "2023-04-20" { // 1444 AH, Eid on 2023-04-21
+			expected = 120 * time.Minute
+		}
+		assertPresetDiff(t, "UmmAlQura", s.Date, "Isha", s.Isha.Sub(s.Maghrib), expected, expected)
+	}
+
+	// The Ramadan follows the Hijri offset in config
+	cfg := prayer.Presets.UmmAlQura(td.Latitude, td.Longitude, td.Timezone)
+	cfg.HijriOffset = -1
+	schedules, err := prayer.Calculate(cfg, 2023)
+	assertNil(t, err, fmt.Sprintf("calculate has error: %v", err))
+	for _, s := range schedules {
+		expected := 90 * time.Minute
+		if s.Date >= "2023-03-24" && s.Date <= "2023-04-21" {
+			expected = 120 * time.Minute
+		}
+		assertPresetDiff(t, "UmmAlQura", s.Date, "Isha", s.Isha.Sub(s.Maghrib), expected, expected)
+	}
+}
+
+func assertPresetDiff(t *testing.T, preset, date, name string, diff, min, max time.Duration) {
+	msg := fmt.Sprintf("%s preset %s in %s: want diff between %v and %v, got %v", preset, name, date, min, max, diff)
+	assertLTE(t, min, diff, msg)
+	assertLTE(t, diff, max, msg)
+}
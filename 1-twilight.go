@@ -53,7 +53,8 @@ func Karachi() *TwilightConvention {
 }
 
 // Diyanet is calculation method from Turkey's Diyanet İşleri Başkanlığı.
-// It has the same value as MWL with Fajr at 18° and Isha at 17°.
+// It has the same value as MWL with Fajr at 18° and Isha at 17°. For the full
+// method including temkin minutes and high latitude handling, use `Presets.Diyanet`.
 func Diyanet() *TwilightConvention {
 	return &TwilightConvention{FajrAngle: 18, IshaAngle: 17}
 }
@@ -1,5 +1,10 @@
 package prayer
 
+import (
+	"fmt"
+	"strings"
+)
+
 // AsrConvention is the convention for calculating Asr time.
 type AsrConvention int
 
@@ -21,3 +26,29 @@ func getAsrCoefficient(cv AsrConvention) float64 {
 	}
 	return 1
 }
+
+// String returns the name of the convention.
+func (cv AsrConvention) String() string {
+	if cv == Hanafi {
+		return "hanafi"
+	}
+	return "shafii"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (cv AsrConvention) MarshalText() ([]byte, error) {
+	return []byte(cv.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (cv *AsrConvention) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "", "shafii":
+		*cv = Shafii
+	case "hanafi":
+		*cv = Hanafi
+	default:
+		return fmt.Errorf("unknown asr convention %q", text)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package prayer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CorrectionFunc is function that returns the corrections for the specified date. It
+// can be used for corrections that vary by date, e.g. monthly corrections or
+// Ramadan-only shifts.
+type CorrectionFunc func(date time.Time) ScheduleCorrections
+
+// CorrectionEntry is the corrections that applied within a date range.
+type CorrectionEntry struct {
+	// Start is the first date where the corrections are applied.
+	Start time.Time
+
+	// End is the last date where the corrections are applied.
+	End time.Time
+
+	// Yearly specify whether the date range is recurring every year. If true, only
+	// the month and day of `Start` and `End` are used, and the range may wrap around
+	// the end of year (e.g. from December 15 to January 15).
+	Yearly bool
+
+	// Corrections is the corrections for each prayer time.
+	Corrections ScheduleCorrections
+}
+
+// CorrectionTable is list of corrections keyed by date range. If there are several
+// entries that match with a date, their corrections will be summed.
+type CorrectionTable []CorrectionEntry
+
+// Corrections returns the corrections for the specified date. It can be used as
+// `DailyCorrections` in config.
+func (ct CorrectionTable) Corrections(date time.Time) ScheduleCorrections {
+	var result ScheduleCorrections
+	for _, e := range ct {
+		if e.contains(date) {
+			result = result.add(e.Corrections)
+		}
+	}
+	return result
+}
+
+func (e CorrectionEntry) contains(date time.Time) bool {
+	if !e.Yearly {
+		day := julianDayNumber(date)
+		return day >= julianDayNumber(e.Start) && day <= julianDayNumber(e.End)
+	}
+
+	day := monthDay(date)
+	start, end := monthDay(e.Start), monthDay(e.End)
+	if start <= end {
+		return day >= start && day <= end
+	}
+	return day >= start || day <= end
+}
+
+// MonthlyCorrections is corrections keyed by month. It's recurring every year.
+type MonthlyCorrections map[time.Month]ScheduleCorrections
+
+// Corrections returns the corrections for the specified date. It can be used as
+// `DailyCorrections` in config.
+func (mc MonthlyCorrections) Corrections(date time.Time) ScheduleCorrections {
+	return mc[date.Month()]
+}
+
+// ParseCorrectionCSV parses correction table from CSV. Each row contains the start
+// date, end date and corrections in minutes for Fajr, Sunrise, Zuhr, Asr, Maghrib and
+// Isha, for example:
+//
+//	# start, end, fajr, sunrise, zuhr, asr, maghrib, isha
+//	01-01, 01-31, 2, -2, 3, 2, 2, 2
+//	2023-03-23, 2023-04-20, 10, 0, 0, 0, 0, 0
+//
+// The date could be in "YYYY-MM-DD" format, or "MM-DD" for range that recurring every
+// year. The corrections may be fractional minutes. Lines started with "#" are ignored,
+// and so does the header row where the first column is "start".
+func ParseCorrectionCSV(r io.Reader) (CorrectionTable, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 8
+	reader.TrimLeadingSpace = true
+
+	var table CorrectionTable
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		// Skip header
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "start") {
+			continue
+		}
+
+		entry, err := parseCorrectionRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("correction row %d: %w", line, err)
+		}
+		table = append(table, entry)
+	}
+
+	return table, nil
+}
+
+func parseCorrectionRecord(record []string) (CorrectionEntry, error) {
+	start, startYearly, err := parseCorrectionDate(record[0])
+	if err != nil {
+		return CorrectionEntry{}, err
+	}
+
+	end, endYearly, err := parseCorrectionDate(record[1])
+	if err != nil {
+		return CorrectionEntry{}, err
+	}
+
+	if startYearly != endYearly {
+		return CorrectionEntry{}, fmt.Errorf("start %q and end %q have different format", record[0], record[1])
+	}
+
+	var durations [6]time.Duration
+	for i := range durations {
+		str := strings.TrimSpace(record[i+2])
+		if str == "" {
+			continue
+		}
+
+		minutes, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return CorrectionEntry{}, fmt.Errorf("invalid correction %q", str)
+		}
+		durations[i] = time.Duration(minutes * float64(time.Minute))
+	}
+
+	return CorrectionEntry{
+		Start:  start,
+		End:    end,
+		Yearly: startYearly,
+		Corrections: ScheduleCorrections{
+			Fajr:    durations[0],
+			Sunrise: durations[1],
+			Zuhr:    durations[2],
+			Asr:     durations[3],
+			Maghrib: durations[4],
+			Isha:    durations[5],
+		},
+	}, nil
+}
+
+func parseCorrectionDate(str string) (time.Time, bool, error) {
+	str = strings.TrimSpace(str)
+	if t, err := time.Parse("2006-01-02", str); err == nil {
+		return t, false, nil
+	}
+
+	// Use leap year so February 29 is valid
+	if t, err := time.Parse("2006-01-02", "2000-"+str); err == nil {
+		return t, true, nil
+	}
+
+	return time.Time{}, false, fmt.Errorf("invalid date %q", str)
+}
+
+func monthDay(t time.Time) int {
+	return int(t.Month())*100 + t.Day()
+}
+
+func (c ScheduleCorrections) add(other ScheduleCorrections) ScheduleCorrections {
+	return ScheduleCorrections{
+		Fajr:    c.Fajr + other.Fajr,
+		Sunrise: c.Sunrise + other.Sunrise,
+		Zuhr:    c.Zuhr + other.Zuhr,
+		Asr:     c.Asr + other.Asr,
+		Maghrib: c.Maghrib + other.Maghrib,
+		Isha:    c.Isha + other.Isha,
+	}
+}
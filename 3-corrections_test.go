@@ -0,0 +1,52 @@
+package prayer_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+	"github.com/hablullah/go-prayer/internal/datatest"
+)
+
+func TestDailyCorrections(t *testing.T) {
+	table, err := prayer.ParseCorrectionCSV(strings.NewReader("" +
+		"start, end, fajr, sunrise, zuhr, asr, maghrib, isha\n" +
+		"# Winter corrections, wrapping around the year\n" +
+		"12-01, 02-28, 2, -2, 0, 0, 0, 0\n" +
+		"2023-03-23, 2023-04-20, 10, 0, 0, 0, 0, 1.5\n"))
+	assertNil(t, err, fmt.Sprintf("parse correction has error: %v", err))
+
+	td := datatest.Jakarta
+	cfg := prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.AstronomicalTwilight(),
+		PreciseToSeconds:   true,
+	}
+
+	precises, _ := prayer.Calculate(cfg, 2023)
+	cfg.DailyCorrections = table.Corrections
+	corrected, _ := prayer.Calculate(cfg, 2023)
+
+	for i, c := range corrected {
+		var expectedFajr, expectedSunrise, expectedIsha time.Duration
+		switch month := c.Zuhr.Month(); {
+		case month <= time.February, month == time.December:
+			expectedFajr, expectedSunrise = 2*time.Minute, -2*time.Minute
+		case c.Date >= "2023-03-23" && c.Date <= "2023-04-20":
+			expectedFajr, expectedIsha = 10*time.Minute, 90*time.Second
+		}
+
+		p := precises[i]
+		msgFormat := "%s correction in %s: want %v got %v"
+		diffFajr := c.Fajr.Sub(p.Fajr)
+		diffSunrise := c.Sunrise.Sub(p.Sunrise)
+		diffIsha := c.Isha.Sub(p.Isha)
+		assertEqual(t, expectedFajr, diffFajr, fmt.Sprintf(msgFormat, "Fajr", c.Date, expectedFajr, diffFajr))
+		assertEqual(t, expectedSunrise, diffSunrise, fmt.Sprintf(msgFormat, "Sunrise", c.Date, expectedSunrise, diffSunrise))
+		assertEqual(t, expectedIsha, diffIsha, fmt.Sprintf(msgFormat, "Isha", c.Date, expectedIsha, diffIsha))
+	}
+}
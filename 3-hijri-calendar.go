@@ -0,0 +1,282 @@
+package prayer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hablullah/go-sampa"
+)
+
+// HijriCalendar is the calendar system that used to determine the Hijri date.
+type HijriCalendar int
+
+const (
+	// HijriTabular is the arithmetical calendar where the months alternately have
+	// 30 and 29 days, with 11 leap years in each 30 years cycle. It's simple and
+	// predictable, but it might be one or two days off from the announced dates.
+	// This is the default calendar.
+	HijriTabular HijriCalendar = iota
+
+	// HijriUmmAlQura is the official calendar of Saudi Arabia. Between 1356 AH (1937
+	// CE) and 1500 AH (2077 CE) the dates follow the official table. Outside of it,
+	// the dates are calculated using the rule that used by the table since 1423 AH
+	// (2002 CE): a month starts on the day after the 29th if on that day the
+	// conjunction occurs before sunset and the Moon sets after the Sun in Mecca.
+	HijriUmmAlQura
+
+	// HijriAstronomical is the calendar that uses visibility criterion of the new
+	// crescent at sunset in the configured location. It uses the criterion of
+	// MABIMS (Brunei, Indonesia, Malaysia and Singapore) since 2021, i.e. the Moon
+	// altitude is at least 3° and the elongation is at least 6.4°. If the Sun
+	// doesn't set in the location (polar day or night), it will be checked in Mecca.
+	HijriAstronomical
+)
+
+// String returns the name of the calendar.
+func (c HijriCalendar) String() string {
+	switch c {
+	case HijriUmmAlQura:
+		return "umm_al_qura"
+	case HijriAstronomical:
+		return "astronomical"
+	default:
+		return "tabular"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c HijriCalendar) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *HijriCalendar) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "", "tabular":
+		*c = HijriTabular
+	case "umm_al_qura":
+		*c = HijriUmmAlQura
+	case "astronomical":
+		*c = HijriAstronomical
+	default:
+		return fmt.Errorf("unknown hijri calendar %q", text)
+	}
+	return nil
+}
+
+// HijriDate is a date in Hijri calendar.
+type HijriDate struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+var hijriMonthNames = [...]string{
+	"Muharram", "Safar", "Rabi al-Awwal", "Rabi al-Thani",
+	"Jumada al-Ula", "Jumada al-Akhirah", "Rajab", "Shaban",
+	"Ramadan", "Shawwal", "Dhu al-Qadah", "Dhu al-Hijjah",
+}
+
+// IsZero reports whether the date is empty.
+func (d HijriDate) IsZero() bool {
+	return d.Year == 0 && d.Month == 0 && d.Day == 0
+}
+
+// MonthName returns the transliterated name of the month, e.g. "Ramadan".
+func (d HijriDate) MonthName() string {
+	if d.Month < 1 || d.Month > 12 {
+		return ""
+	}
+	return hijriMonthNames[d.Month-1]
+}
+
+// String returns the date in "YYYY-MM-DD" format.
+func (d HijriDate) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// ToHijri returns the Hijri date of the specified date, using the calendar, location
+// and day offset in the configuration.
+func ToHijri(cfg Config, date time.Time) (HijriDate, error) {
+	tz, err := cfg.location()
+	if err != nil {
+		return HijriDate{}, err
+	}
+
+	cfg.Timezone = tz
+	return newHijriConverter(cfg).convert(date.In(tz))
+}
+
+// hijriConverter converts Gregorian date into Hijri date. It caches the start of
+// months, so it's efficient for converting many consecutive days.
+type hijriConverter struct {
+	sync.Mutex
+	calendar        HijriCalendar
+	offset          int
+	location        sampa.Location
+	timezone        *time.Location
+	nextMonthStarts map[int]int
+}
+
+func newHijriConverter(cfg Config) *hijriConverter {
+	return &hijriConverter{
+		calendar: cfg.HijriCalendar,
+		offset:   cfg.HijriOffset,
+		timezone: cfg.Timezone,
+		location: sampa.Location{
+			Latitude:  cfg.Latitude,
+			Longitude: cfg.Longitude,
+			Elevation: cfg.Elevation,
+		},
+		nextMonthStarts: map[int]int{},
+	}
+}
+
+func (hc *hijriConverter) convert(date time.Time) (HijriDate, error) {
+	date = time.Date(date.Year(), date.Month(), date.Day()+hc.offset, 0, 0, 0, 0, date.Location())
+	year, month, day := tabularHijriDate(date)
+	if hc.calendar != HijriUmmAlQura && hc.calendar != HijriAstronomical {
+		return HijriDate{year, month, day}, nil
+	}
+
+	// Use the official table if possible
+	jdn := julianDayNumber(date)
+	if hc.calendar == HijriUmmAlQura {
+		if hijriDate, inTable := ummAlQuraTableDate(jdn); inTable {
+			return hijriDate, nil
+		}
+	}
+
+	hc.Lock()
+	defer hc.Unlock()
+
+	// Since the difference with tabular calendar is only a few days, start from the
+	// tabular beginning of previous year then follow the months until the date. The
+	// error in the starting point will be corrected after a few months.
+	year, month = year-1, 1
+	monthStart := tabularHijriToJDN(year, month, 1)
+	for {
+		next, err := hc.nextMonthStart(monthStart)
+		if err != nil {
+			return HijriDate{}, err
+		}
+
+		if next > jdn {
+			return HijriDate{year, month, jdn - monthStart + 1}, nil
+		}
+
+		monthStart = next
+		if month++; month > 12 {
+			year, month = year+1, 1
+		}
+	}
+}
+
+// nextMonthStart returns the Julian day number of the first day of the next month.
+// The crescent is checked on the evening of the 29th day: if it's accepted, the
+// next month starts on the following day, otherwise the month is completed into 30
+// days (istikmal).
+func (hc *hijriConverter) nextMonthStart(monthStart int) (int, error) {
+	if next, exist := hc.nextMonthStarts[monthStart]; exist {
+		return next, nil
+	}
+
+	location, tz := hc.location, hc.timezone
+	if hc.calendar == HijriUmmAlQura {
+		location = sampa.Location{Latitude: kaabaLatitude, Longitude: kaabaLongitude}
+		tz = meccaTimezone()
+	}
+
+	day29 := jdnToTime(monthStart + 28)
+	evening := time.Date(day29.Year(), day29.Month(), day29.Day(), 0, 0, 0, 0, tz)
+	accepted, err := hc.crescentAccepted(location, evening)
+	if err != nil {
+		return 0, err
+	}
+
+	next := monthStart + 30
+	if accepted {
+		next = monthStart + 29
+	}
+
+	hc.nextMonthStarts[monthStart] = next
+	return next, nil
+}
+
+// crescentAccepted reports whether the new crescent is accepted on the evening of
+// the specified date, according to the calendar's criterion.
+func (hc *hijriConverter) crescentAccepted(location sampa.Location, date time.Time) (bool, error) {
+	sun, err := sampa.GetSunEvents(date, location, nil)
+	if err != nil {
+		return false, err
+	}
+
+	// If the Sun doesn't set, check it in Mecca instead
+	sunset := sun.Sunset.DateTime
+	if sunset.IsZero() {
+		mecca := sampa.Location{Latitude: kaabaLatitude, Longitude: kaabaLongitude}
+		if location == mecca {
+			return false, nil
+		}
+
+		meccaDate := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, meccaTimezone())
+		return hc.crescentAccepted(mecca, meccaDate)
+	}
+
+	// The conjunction must occur before sunset
+	if conjunction := nearestConjunction(sunset); !conjunction.Before(sunset) {
+		return false, nil
+	}
+
+	switch hc.calendar {
+	case HijriUmmAlQura:
+		// Moon must set after the Sun. If the Moon doesn't set in this day, it
+		// means it sets after midnight, which is after the sunset.
+		moon, err := sampa.GetMoonEvents(date, location, nil)
+		if err != nil {
+			return false, err
+		}
+
+		moonset := moon.Moonset.DateTime
+		return moonset.IsZero() || moonset.After(sunset), nil
+
+	default:
+		moon, err := sampa.GetMoonPosition(sunset, location, nil)
+		if err != nil {
+			return false, err
+		}
+
+		return moon.TopocentricElevationAngle >= 3 && moon.Elongation >= 6.4, nil
+	}
+}
+
+// nearestConjunction returns the conjunction (new moon) that nearest to t.
+func nearestConjunction(t time.Time) time.Time {
+	phases := sampa.GetMoonPhases(t, nil)
+	nearest := phases.NewMoon
+	if phases.NextNewMoon.Sub(t).Abs() < nearest.Sub(t).Abs() {
+		nearest = phases.NextNewMoon
+	}
+
+	// The phases are calculated around t, so also check the previous month
+	prevPhases := sampa.GetMoonPhases(t.AddDate(0, 0, -20), nil)
+	if prevPhases.NewMoon.Sub(t).Abs() < nearest.Sub(t).Abs() {
+		nearest = prevPhases.NewMoon
+	}
+
+	return nearest
+}
+
+func jdnToTime(jdn int) time.Time {
+	return time.Unix(int64(jdn-2440588)*86400, 0).UTC()
+}
+
+func meccaTimezone() *time.Location {
+	tz, err := time.LoadLocation("Asia/Riyadh")
+	if err != nil {
+		return time.FixedZone("AST", 3*60*60)
+	}
+	return tz
+}
@@ -0,0 +1,40 @@
+package prayer
+
+import (
+	"math"
+	"time"
+)
+
+// hijriEpoch is the Julian day number of 1 Muharram 1 AH in arithmetical calendar,
+// i.e. 16 July 622 CE in Julian calendar.
+const hijriEpoch = 1948440
+
+// tabularHijriDate converts the date of t into Hijri date using arithmetical (tabular)
+// calendar, where leap years are 2, 5, 7, 10, 13, 16, 18, 21, 24, 26 and 29 in each
+// 30 years cycle.
+func tabularHijriDate(t time.Time) (year, month, day int) {
+	jdn := julianDayNumber(t)
+	year = int(math.Floor(float64(30*(jdn-hijriEpoch)+10646) / 10631))
+	month = int(math.Ceil(float64(jdn-29-tabularHijriToJDN(year, 1, 1))/29.5)) + 1
+	if month > 12 {
+		month = 12
+	} else if month < 1 {
+		month = 1
+	}
+	day = jdn - tabularHijriToJDN(year, month, 1) + 1
+	return
+}
+
+func tabularHijriToJDN(year, month, day int) int {
+	return day +
+		int(math.Ceil(29.5*float64(month-1))) +
+		(year-1)*354 +
+		int(math.Floor(float64(3+11*year)/30)) +
+		hijriEpoch - 1
+}
+
+// julianDayNumber returns the Julian day number for the date of t.
+func julianDayNumber(t time.Time) int {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(date.Unix()/86400) + 2440588
+}
@@ -0,0 +1,101 @@
+package prayer_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+	"github.com/hablullah/go-prayer/internal/datatest"
+)
+
+func TestHijri(t *testing.T) {
+	// Umm al-Qura dates in 1444-1445 AH
+	ummAlQura := prayer.Config{HijriCalendar: prayer.HijriUmmAlQura}
+	testHijri(t, ummAlQura, "2023-03-23", "1444-09-01")
+	testHijri(t, ummAlQura, "2023-04-20", "1444-09-29")
+	testHijri(t, ummAlQura, "2023-04-21", "1444-10-01")
+	testHijri(t, ummAlQura, "2023-06-19", "1444-12-01")
+	testHijri(t, ummAlQura, "2023-07-19", "1445-01-01")
+	testHijri(t, ummAlQura, "2024-03-11", "1445-09-01")
+	testHijri(t, ummAlQura, "2024-04-10", "1445-10-01")
+	testHijri(t, ummAlQura, "2024-07-07", "1446-01-01")
+
+	// Samples of the official Umm al-Qura table, including the dates before 1423 AH
+	// where the table doesn't follow the current rule
+	testHijri(t, ummAlQura, "1937-03-14", "1356-01-01")
+	testHijri(t, ummAlQura, "1990-05-24", "1410-10-29")
+	testHijri(t, ummAlQura, "1990-05-25", "1410-11-01")
+	testHijri(t, ummAlQura, "1994-05-10", "1414-11-29")
+	testHijri(t, ummAlQura, "1994-05-11", "1414-12-01")
+	testHijri(t, ummAlQura, "1999-05-15", "1420-01-29")
+	testHijri(t, ummAlQura, "1999-05-16", "1420-02-01")
+	testHijri(t, ummAlQura, "2001-05-23", "1422-02-29")
+	testHijri(t, ummAlQura, "2001-05-24", "1422-03-01")
+	testHijri(t, ummAlQura, "2008-05-05", "1429-04-29")
+	testHijri(t, ummAlQura, "2008-05-06", "1429-05-01")
+	testHijri(t, ummAlQura, "2015-05-18", "1436-07-29")
+	testHijri(t, ummAlQura, "2015-05-19", "1436-08-01")
+	testHijri(t, ummAlQura, "2026-05-17", "1447-11-30")
+	testHijri(t, ummAlQura, "2026-05-18", "1447-12-01")
+	testHijri(t, ummAlQura, "2035-05-08", "1457-02-30")
+	testHijri(t, ummAlQura, "2035-05-09", "1457-03-01")
+	testHijri(t, ummAlQura, "2049-05-02", "1471-07-30")
+	testHijri(t, ummAlQura, "2049-05-03", "1471-08-01")
+	testHijri(t, ummAlQura, "2077-11-16", "1500-12-30")
+
+	// Outside of the table, the dates continue using the rule
+	testHijri(t, ummAlQura, "1937-03-13", "1355-12-30")
+	testHijri(t, ummAlQura, "2077-11-17", "1501-01-01")
+
+	// Indonesian government dates with MABIMS criterion in 1444 AH
+	jakarta := prayer.Config{
+		Latitude:      datatest.Jakarta.Latitude,
+		Longitude:     datatest.Jakarta.Longitude,
+		Timezone:      datatest.Jakarta.Timezone,
+		HijriCalendar: prayer.HijriAstronomical,
+	}
+	testHijri(t, jakarta, "2023-03-23", "1444-09-01")
+	testHijri(t, jakarta, "2023-04-21", "1444-09-30")
+	testHijri(t, jakarta, "2023-04-22", "1444-10-01")
+	testHijri(t, jakarta, "2023-06-29", "1444-12-10")
+
+	// Tabular calendar with offset
+	tabular := prayer.Config{HijriOffset: -1}
+	testHijri(t, tabular, "2023-03-24", "1444-09-01")
+}
+
+func testHijri(t *testing.T, cfg prayer.Config, date string, expected string) {
+	dt, _ := time.Parse("2006-01-02", date)
+	hijri, err := prayer.ToHijri(cfg, dt)
+	assertNil(t, err, fmt.Sprintf("hijri of %s has error: %v", date, err))
+
+	msg := fmt.Sprintf("%s hijri of %s: want %s got %s", cfg.HijriCalendar, date, expected, hijri)
+	assertEqual(t, expected, hijri.String(), msg)
+}
+
+func TestScheduleHijri(t *testing.T) {
+	schedules, err := prayer.Calculate(prayer.Config{
+		Latitude:      datatest.Tromso.Latitude,
+		Longitude:     datatest.Tromso.Longitude,
+		Timezone:      datatest.Tromso.Timezone,
+		HijriCalendar: prayer.HijriAstronomical,
+		IncludeHijri:  true,
+	}, 2023)
+	assertNil(t, err, fmt.Sprintf("calculate has error: %v", err))
+
+	// Days must be consecutive, even in polar days and nights
+	for i := 1; i < len(schedules); i++ {
+		prev, curr := schedules[i-1].Hijri, schedules[i].Hijri
+		msg := fmt.Sprintf("hijri in %s is not consecutive: %s => %s", schedules[i].Date, prev, curr)
+		if curr.Day == 1 {
+			assertEqual(t, true, prev.Day == 29 || prev.Day == 30, msg)
+			assertEqual(t, prev.Month%12+1, curr.Month, msg)
+		} else {
+			assertEqual(t, prev.Day+1, curr.Day, msg)
+			assertEqual(t, prev.Month, curr.Month, msg)
+		}
+	}
+
+	assertEqual(t, "Ramadan", schedules[100].Hijri.MonthName(), "wrong month name")
+}
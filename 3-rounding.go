@@ -0,0 +1,139 @@
+package prayer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RoundingMethod is the method for rounding the prayer times.
+type RoundingMethod int
+
+const (
+	// RoundNearest rounds the time to the nearest multiple of granularity. This
+	// is the default rounding method.
+	RoundNearest RoundingMethod = iota
+
+	// RoundCeil rounds the time up to the next multiple of granularity. This is
+	// the safe rounding for starting times like Fajr, Zuhr, Asr, Maghrib and Isha,
+	// since it will never move the time earlier (ihtiyat).
+	RoundCeil
+
+	// RoundFloor rounds the time down to the previous multiple of granularity. This
+	// is the safe rounding for ending boundary like sunrise.
+	RoundFloor
+
+	// RoundNone keeps the time as it is, precise to seconds.
+	RoundNone
+)
+
+// String returns the name of the rounding method.
+func (m RoundingMethod) String() string {
+	switch m {
+	case RoundCeil:
+		return "ceil"
+	case RoundFloor:
+		return "floor"
+	case RoundNone:
+		return "none"
+	default:
+		return "nearest"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (m RoundingMethod) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (m *RoundingMethod) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "", "nearest":
+		*m = RoundNearest
+	case "ceil":
+		*m = RoundCeil
+	case "floor":
+		*m = RoundFloor
+	case "none":
+		*m = RoundNone
+	default:
+		return fmt.Errorf("unknown rounding method %q", text)
+	}
+	return nil
+}
+
+// Rounding is the rounding rule for a prayer time.
+type Rounding struct {
+	// Method is the rounding method. By default it will use `RoundNearest`.
+	Method RoundingMethod
+
+	// Granularity is the multiple of rounded time, e.g. 1, 2 or 5 minutes. If not
+	// specified, it will use 1 minute.
+	Granularity time.Duration
+}
+
+// RoundingPolicy is the rounding rule for each prayer time.
+type RoundingPolicy struct {
+	Fajr    Rounding
+	Sunrise Rounding
+	Zuhr    Rounding
+	Asr     Rounding
+	Maghrib Rounding
+	Isha    Rounding
+}
+
+// UniformRounding returns rounding policy that use the same rounding for every
+// prayer times.
+func UniformRounding(method RoundingMethod, granularity time.Duration) *RoundingPolicy {
+	r := Rounding{Method: method, Granularity: granularity}
+	return &RoundingPolicy{
+		Fajr:    r,
+		Sunrise: r,
+		Zuhr:    r,
+		Asr:     r,
+		Maghrib: r,
+		Isha:    r,
+	}
+}
+
+// SafeRounding returns rounding policy where the starting times are rounded up and
+// sunrise is rounded down, so the rounded times are always within the actual
+// prayer period.
+func SafeRounding(granularity time.Duration) *RoundingPolicy {
+	policy := UniformRounding(RoundCeil, granularity)
+	policy.Sunrise.Method = RoundFloor
+	return policy
+}
+
+func applyRounding(t time.Time, r Rounding) time.Time {
+	// If time is empty or rounding is not needed, return as it is
+	if t.IsZero() || r.Method == RoundNone {
+		return t
+	}
+
+	// Use wall clock instead of absolute time, so the rounding works properly
+	// in timezone with odd UTC offset (e.g. +05:45).
+	granularity := r.Granularity
+	if granularity <= 0 {
+		granularity = time.Minute
+	}
+
+	ct := clockTime(t)
+	remainder := ct % granularity
+	if remainder == 0 {
+		return t
+	}
+
+	rounded := ct - remainder
+	switch r.Method {
+	case RoundCeil:
+		rounded += granularity
+	case RoundNearest:
+		if remainder*2 >= granularity {
+			rounded += granularity
+		}
+	}
+
+	return atClockTime(t, rounded)
+}
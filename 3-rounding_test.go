@@ -0,0 +1,48 @@
+package prayer_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+	"github.com/hablullah/go-prayer/internal/datatest"
+)
+
+func TestRounding(t *testing.T) {
+	td := datatest.Jakarta
+	cfg := prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.AstronomicalTwilight(),
+		PreciseToSeconds:   true,
+	}
+
+	precises, _ := prayer.Calculate(cfg, 2023)
+	cfg.Rounding = prayer.SafeRounding(2 * time.Minute)
+	roundeds, _ := prayer.Calculate(cfg, 2023)
+
+	for i, r := range roundeds {
+		p := precises[i]
+		assertRounding(t, p.Date, "Fajr", p.Fajr, r.Fajr, true)
+		assertRounding(t, p.Date, "Sunrise", p.Sunrise, r.Sunrise, false)
+		assertRounding(t, p.Date, "Zuhr", p.Zuhr, r.Zuhr, true)
+		assertRounding(t, p.Date, "Asr", p.Asr, r.Asr, true)
+		assertRounding(t, p.Date, "Maghrib", p.Maghrib, r.Maghrib, true)
+		assertRounding(t, p.Date, "Isha", p.Isha, r.Isha, true)
+	}
+}
+
+func assertRounding(t *testing.T, date, name string, precise, rounded time.Time, ceil bool) {
+	msg := fmt.Sprintf("%s rounding in %s: precise %s rounded %s", name, date, precise, rounded)
+	assertEqual(t, 0, rounded.Minute()%2, msg)
+	assertEqual(t, 0, rounded.Second(), msg)
+
+	diff := rounded.Sub(precise)
+	if !ceil {
+		diff = -diff
+	}
+	assertLTE(t, 0, diff, msg)
+	assertLTE(t, diff, 2*time.Minute, msg)
+}
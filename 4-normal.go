@@ -8,6 +8,31 @@ import (
 )
 
 func calcNormal(cfg Config, year int) ([]Schedule, int) {
+	// Calculate schedules for each day in a year.
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, cfg.Timezone)
+	limit := start.AddDate(1, 0, 0)
+	nDays := int(limit.Sub(start).Hours() / 24)
+
+	days := make([]int, nDays)
+	for i := range days {
+		days[i] = i
+	}
+
+	schedules := calcNormalDays(cfg, year, days)
+	var nAbnormal int
+	for _, s := range schedules {
+		if !s.IsNormal {
+			nAbnormal++
+		}
+	}
+
+	return schedules, nAbnormal
+}
+
+// calcNormalDays calculates the schedules only in the specified days of the year,
+// where 0 is January 1. It's used when only a few days are needed, e.g. while
+// searching the reference latitude in `AqrabAlBilad`.
+func calcNormalDays(cfg Config, year int, days []int) []Schedule {
 	// Prepare location
 	location := sampa.Location{
 		Latitude:  cfg.Latitude,
@@ -43,19 +68,13 @@ func calcNormal(cfg Config, year int) ([]Schedule, int) {
 		},
 	}}
 
-	// Calculate schedules for each day in a year.
-	start := time.Date(year, 1, 1, 0, 0, 0, 0, cfg.Timezone)
-	limit := start.AddDate(1, 0, 0)
-	nDays := int(limit.Sub(start).Hours() / 24)
-
 	// Create slice to contain result
-	schedules := make([]Schedule, nDays)
+	schedules := make([]Schedule, len(days))
 
 	// Calculate each day
-	var idx int
-	var nAbnormal int
-	for dt := start; dt.Before(limit); dt = dt.AddDate(0, 0, 1) {
+	for idx, day := range days {
 		// Calculate the events
+		dt := time.Date(year, 1, day+1, 0, 0, 0, 0, cfg.Timezone)
 		e, _ := sampa.GetSunEvents(dt, location, nil, customEvents...)
 
 		// Create the prayer schedule
@@ -78,13 +97,9 @@ func calcNormal(cfg Config, year int) ([]Schedule, int) {
 
 		// Save the schedule
 		schedules[idx] = s
-		if !s.IsNormal {
-			nAbnormal++
-		}
-		idx++
 	}
 
-	return schedules, nAbnormal
+	return schedules
 }
 
 func radToDeg(rad float64) float64 {
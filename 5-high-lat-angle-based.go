@@ -12,8 +12,9 @@ import (
 // part. So, if the twilight angle for Isha is 15, then Isha begins at the end of the
 // first quarter (15/60) of the night. Time for Fajr is calculated similarly.
 //
-// This adapter depends on sunrise and sunset time, so it might not be suitable for
-// area in extreme latitudes (>=65 degrees).
+// In extreme latitudes (>=65 degrees) where the Sun doesn't rise or set, this adapter
+// uses virtual night whose sunrise and sunset are interpolated from the last and the
+// first days that have them, relative to the transit time.
 //
 // Reference: http://praytimes.org/calculation
 func AngleBased() HighLatitudeAdapter {
@@ -39,22 +40,26 @@ func highLatAngleBased(cfg Config, year int, schedules []Schedule) []Schedule {
 	}
 
 	// Apply schedules
+	// In polar day or night, use the virtual sunrise and Maghrib
+	sunrises, maghribs := virtualSunTimes(schedules)
+
 	for i, s := range schedules {
 		// Angle based require Sunrise and Maghrib, and only done if Fajr or Isha missing
-		if !s.Sunrise.IsZero() && !s.Maghrib.IsZero() && (s.Fajr.IsZero() || s.Isha.IsZero()) {
+		sunrise, maghrib := sunrises[i], maghribs[i]
+		if !sunrise.IsZero() && !maghrib.IsZero() && (s.Fajr.IsZero() || s.Isha.IsZero()) {
 			// Calculate night duration
-			dayDuration := s.Maghrib.Sub(s.Sunrise).Seconds()
+			dayDuration := maghrib.Sub(sunrise).Seconds()
 			nightDuration := float64(24*60*60) - dayDuration
 
 			// Calculate Fajr time
 			fajrPercentage := fajrAngle / 60
 			fajrDuration := nightDuration * fajrPercentage * float64(time.Second)
-			schedules[i].Fajr = s.Sunrise.Add(-time.Duration(fajrDuration))
+			schedules[i].Fajr = sunrise.Add(-time.Duration(fajrDuration))
 
 			// Calculate Isha time
 			ishaPercentage := ishaAngle / 60
 			ishaDuration := nightDuration * ishaPercentage * float64(time.Second)
-			schedules[i].Isha = s.Maghrib.Add(time.Duration(ishaDuration))
+			schedules[i].Isha = maghrib.Add(time.Duration(ishaDuration))
 		}
 	}
 
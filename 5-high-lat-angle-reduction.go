@@ -0,0 +1,193 @@
+package prayer
+
+import (
+	"time"
+)
+
+// AngleReductionConfig is configuration for `AngleReduction` adapter.
+type AngleReductionConfig struct {
+	// Step is the reduction of the twilight angle in each try, in degrees. If not
+	// specified, it will use 1 degree.
+	Step float64
+
+	// MinAngle is the smallest twilight angle that can be used, in degrees. If not
+	// specified, it will use 12 degrees (nautical twilight).
+	MinAngle float64
+
+	// SmoothingDays is the number of days used for smoothing the times in the days
+	// around the reduced angle. If not specified, it will use 15 days. Use 1 to disable
+	// the smoothing.
+	SmoothingDays int
+}
+
+// AngleReduction is adapter used by many timetables in Scandinavia. If the Sun
+// doesn't reach the twilight angle in a night, the angle is reduced step by step,
+// e.g. from 18° into 17°, 16° and so on until a minimum angle like 12°. The first
+// angle that reached in that night is used for Fajr and Isha. Since the times jump
+// every time the angle changes, they are smoothed across the days with reduced angle
+// using moving average, relative to the transit time. The normal days around them
+// are smoothed as well, but their times are only allowed to be moved later.
+//
+// The reduced times are calculated using the same Sun events as the normal times, so
+// it works with any `TwilightConvention`. If the Sun doesn't reach the minimum angle,
+// Fajr and Isha are left as they are, so for area in extreme latitudes (>=65 degrees)
+// it should be combined with other adapter.
+func AngleReduction(arCfg AngleReductionConfig) HighLatitudeAdapter {
+	// Use the registered adapter when possible, so the config can be encoded
+	if arCfg.withDefaults() == (AngleReductionConfig{}).withDefaults() {
+		return highLatAngleReduction
+	}
+
+	return func(cfg Config, year int, schedules []Schedule) []Schedule {
+		return applyAngleReduction(cfg, year, schedules, arCfg)
+	}
+}
+
+func highLatAngleReduction(cfg Config, year int, schedules []Schedule) []Schedule {
+	return applyAngleReduction(cfg, year, schedules, AngleReductionConfig{})
+}
+
+func (arCfg AngleReductionConfig) withDefaults() AngleReductionConfig {
+	if arCfg.Step <= 0 {
+		arCfg.Step = 1
+	}
+
+	if arCfg.MinAngle <= 0 {
+		arCfg.MinAngle = 12
+	}
+
+	if arCfg.SmoothingDays <= 0 {
+		arCfg.SmoothingDays = 15
+	}
+
+	return arCfg
+}
+
+func applyAngleReduction(cfg Config, year int, schedules []Schedule, arCfg AngleReductionConfig) []Schedule {
+	// Apply default config
+	arCfg = arCfg.withDefaults()
+
+	// Find the days where Fajr or Isha is missing
+	nSchedules := len(schedules)
+	fajrReduced := make([]bool, nSchedules)
+	ishaReduced := make([]bool, nSchedules)
+	nMissing := 0
+	for i, s := range schedules {
+		if s.Fajr.IsZero() {
+			fajrReduced[i] = true
+			nMissing++
+		}
+
+		if s.Isha.IsZero() {
+			ishaReduced[i] = true
+			nMissing++
+		}
+	}
+
+	// Reduce the angle step by step, until every missing times are found or the
+	// minimum angle is reached.
+	tc := cfg.TwilightConvention
+	fajrAngle, ishaAngle := tc.FajrAngle, tc.IshaAngle
+	for nMissing > 0 && (fajrAngle > arCfg.MinAngle || ishaAngle > arCfg.MinAngle) {
+		fajrAngle = maxFloat(fajrAngle-arCfg.Step, arCfg.MinAngle)
+		ishaAngle = maxFloat(ishaAngle-arCfg.Step, arCfg.MinAngle)
+
+		reducedCfg := cfg
+		reducedCfg.TwilightConvention = &TwilightConvention{
+			FajrAngle:       fajrAngle,
+			IshaAngle:       ishaAngle,
+			MaghribDuration: tc.MaghribDuration,
+		}
+		reducedSchedules, _ := calcNormal(reducedCfg, year)
+
+		for i, rs := range reducedSchedules {
+			if fajrReduced[i] && schedules[i].Fajr.IsZero() && !rs.Fajr.IsZero() {
+				schedules[i].Fajr = rs.Fajr
+				nMissing--
+			}
+
+			if ishaReduced[i] && schedules[i].Isha.IsZero() && !rs.Isha.IsZero() {
+				schedules[i].Isha = rs.Isha
+				nMissing--
+			}
+		}
+	}
+
+	// Smooth the times in the days with reduced angle
+	fajrDurations := make([]time.Duration, nSchedules)
+	ishaDurations := make([]time.Duration, nSchedules)
+	for i, s := range schedules {
+		if !s.Fajr.IsZero() {
+			fajrDurations[i] = s.Zuhr.Sub(s.Fajr)
+		}
+
+		if !s.Isha.IsZero() {
+			ishaDurations[i] = s.Isha.Sub(s.Zuhr)
+		}
+	}
+
+	// The normal days around the reduced days are smoothed as well to prevent the
+	// jump, but they are only allowed to be later than the calculated times.
+	halfWindow := arCfg.SmoothingDays / 2
+	fajrNearby := nearbyFlags(fajrReduced, halfWindow)
+	ishaNearby := nearbyFlags(ishaReduced, halfWindow)
+	for i, s := range schedules {
+		if fajrNearby[i] && !s.Fajr.IsZero() {
+			fajr := s.Zuhr.Add(-movingAverage(fajrDurations, i, halfWindow))
+			if fajrReduced[i] || fajr.After(s.Fajr) {
+				schedules[i].Fajr = fajr
+			}
+		}
+
+		if ishaNearby[i] && !s.Isha.IsZero() {
+			isha := s.Zuhr.Add(movingAverage(ishaDurations, i, halfWindow))
+			if ishaReduced[i] || isha.After(s.Isha) {
+				schedules[i].Isha = isha
+			}
+		}
+	}
+
+	return schedules
+}
+
+// nearbyFlags returns flags that marks the days within the distance of flagged days.
+func nearbyFlags(flags []bool, distance int) []bool {
+	nearby := make([]bool, len(flags))
+	for i, flagged := range flags {
+		if !flagged {
+			continue
+		}
+
+		for j := i - distance; j <= i+distance; j++ {
+			if j >= 0 && j < len(flags) {
+				nearby[j] = true
+			}
+		}
+	}
+	return nearby
+}
+
+// movingAverage returns the average of non zero durations around the specified index.
+func movingAverage(durations []time.Duration, idx, halfWindow int) time.Duration {
+	var n int
+	var sum time.Duration
+	for i := idx - halfWindow; i <= idx+halfWindow; i++ {
+		if i < 0 || i >= len(durations) || durations[i] == 0 {
+			continue
+		}
+		sum += durations[i]
+		n++
+	}
+
+	if n == 0 {
+		return durations[idx]
+	}
+	return sum / time.Duration(n)
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,212 @@
+package prayer
+
+import (
+	"math"
+	"sort"
+)
+
+// Coordinate is a location on Earth.
+type Coordinate struct {
+	Latitude  float64
+	Longitude float64
+	Elevation float64
+}
+
+// AqrabAlBiladConfig is configuration for `AqrabAlBilad` adapter.
+type AqrabAlBiladConfig struct {
+	// Reference is the location of the reference city. If not specified, the
+	// reference is searched along the meridian of the location.
+	Reference *Coordinate
+
+	// PerDay specify whether the reference latitude is searched for each abnormal
+	// day. By default it's searched for each abnormal period, so the entire period
+	// follows the same latitude.
+	PerDay bool
+
+	// Step is the step of latitude while searching the reference, in degrees. If not
+	// specified, it will use 0.5 degree.
+	Step float64
+}
+
+// AqrabAlBilad is adapter based on the classical juristic opinion that the people in
+// abnormal location follow the nearest city (aqrab al-bilad) that still has normal
+// day and night. Here the day is considered "abnormal" when any of Fajr, sunrise,
+// Maghrib or Isha doesn't exist using the chosen convention.
+//
+// By default the nearest city is searched along the meridian of the location, i.e.
+// the nearest latitude toward the equator where the days are normal. Unlike
+// `NearestLatitude` which uses fixed 45 degrees, the search is done for each abnormal
+// period (or each abnormal day if `PerDay` is enabled). Alternatively, you can specify
+// the reference city explicitly.
+//
+// In the abnormal days, the schedule follows the reference using transit time as the
+// common point, the same as `Mecca` adapter.
+//
+// This adapter doesn't require the sunrise and sunset to be exist in a day, so it's
+// usable for area in extreme latitudes (>=65 degrees).
+func AqrabAlBilad(abCfg AqrabAlBiladConfig) HighLatitudeAdapter {
+	// Use the registered adapter when possible, so the config can be encoded
+	if abCfg.Reference == nil && !abCfg.PerDay && (abCfg.Step <= 0 || abCfg.Step == 0.5) {
+		return highLatAqrabAlBilad
+	}
+
+	return func(cfg Config, year int, schedules []Schedule) []Schedule {
+		return applyAqrabAlBilad(cfg, year, schedules, abCfg)
+	}
+}
+
+func highLatAqrabAlBilad(cfg Config, year int, schedules []Schedule) []Schedule {
+	return applyAqrabAlBilad(cfg, year, schedules, AqrabAlBiladConfig{})
+}
+
+func applyAqrabAlBilad(cfg Config, year int, schedules []Schedule, abCfg AqrabAlBiladConfig) []Schedule {
+	// Apply default config
+	if abCfg.Step <= 0 {
+		abCfg.Step = 0.5
+	}
+
+	// Find the abnormal days
+	tc := cfg.TwilightConvention
+	nSchedules := len(schedules)
+	flaggedSchedules := make([]Schedule, nSchedules)
+	for i, s := range schedules {
+		flaggedSchedules[i] = s
+		flaggedSchedules[i].IsNormal = isCompleteSchedule(s, tc)
+	}
+
+	// Prepare function to calculate schedules in other location. Only the requested
+	// days are calculated, and they are cached so each day is calculated only once
+	// for each location.
+	cache := map[aqrabReferenceKey]map[int]Schedule{}
+	calcReference := func(coord Coordinate, indexes []int) map[int]Schedule {
+		key := aqrabReferenceKey{coord, *tc}
+		refSchedules, exist := cache[key]
+		if !exist {
+			refSchedules = map[int]Schedule{}
+			cache[key] = refSchedules
+		}
+
+		var missing []int
+		for _, i := range indexes {
+			if _, exist := refSchedules[i]; !exist {
+				missing = append(missing, i)
+			}
+		}
+
+		if len(missing) > 0 {
+			refCfg := Config{
+				Latitude:           coord.Latitude,
+				Longitude:          coord.Longitude,
+				Elevation:          coord.Elevation,
+				Timezone:           cfg.Timezone,
+				TwilightConvention: tc,
+				AsrConvention:      cfg.AsrConvention}
+			for n, s := range calcNormalDays(refCfg, year, missing) {
+				refSchedules[missing[n]] = s
+			}
+		}
+
+		return refSchedules
+	}
+
+	// If reference is specified, just use it
+	if ref := abCfg.Reference; ref != nil {
+		var indexes []int
+		for i, s := range flaggedSchedules {
+			if !s.IsNormal {
+				indexes = append(indexes, i)
+			}
+		}
+
+		refSchedules := calcReference(*ref, indexes)
+		for _, i := range indexes {
+			if isCompleteSchedule(refSchedules[i], tc) {
+				schedules[i] = applyReferenceSchedule(schedules[i], refSchedules[i])
+			}
+		}
+		return schedules
+	}
+
+	// Group the abnormal days that will follow the same latitude
+	var groups [][]int
+	if abCfg.PerDay {
+		for i, s := range flaggedSchedules {
+			if !s.IsNormal {
+				groups = append(groups, []int{i})
+			}
+		}
+	} else {
+		abnormalSummer, abnormalWinter := extractAbnormalSchedules(flaggedSchedules)
+		for _, as := range []abnormalRange{abnormalSummer, abnormalWinter} {
+			if !as.IsEmpty() {
+				groups = append(groups, as.Indexes)
+			}
+		}
+	}
+
+	// Search the nearest latitude toward equator where every days in the group are
+	// normal, then apply its schedules. The nearer a latitude to the equator, the
+	// more likely its days are normal, so it can be searched using bisection.
+	direction := -math.Copysign(1, cfg.Latitude)
+	nSteps := int(math.Abs(cfg.Latitude) / abCfg.Step)
+	for _, indexes := range groups {
+		referenceAt := func(step int) (Coordinate, map[int]Schedule) {
+			coord := Coordinate{
+				Latitude:  cfg.Latitude + direction*float64(step)*abCfg.Step,
+				Longitude: cfg.Longitude,
+				Elevation: cfg.Elevation,
+			}
+			return coord, calcReference(coord, indexes)
+		}
+
+		allNormal := func(step int) bool {
+			_, refSchedules := referenceAt(step)
+			for _, i := range indexes {
+				if !isCompleteSchedule(refSchedules[i], tc) {
+					return false
+				}
+			}
+			return true
+		}
+
+		step := 1 + sort.Search(nSteps, func(n int) bool { return allNormal(n + 1) })
+		if step > nSteps {
+			continue
+		}
+
+		_, refSchedules := referenceAt(step)
+		for _, i := range indexes {
+			schedules[i] = applyReferenceSchedule(schedules[i], refSchedules[i])
+		}
+	}
+
+	return schedules
+}
+
+// aqrabReferenceKey is the key for caching the reference schedules in `AqrabAlBilad`.
+type aqrabReferenceKey struct {
+	Coordinate
+	convention TwilightConvention
+}
+
+// isCompleteSchedule reports whether Fajr, sunrise, Maghrib and Isha exist in the
+// schedule. Isha is not checked if it's fixed after Maghrib.
+func isCompleteSchedule(s Schedule, tc *TwilightConvention) bool {
+	if s.Fajr.IsZero() || s.Sunrise.IsZero() || s.Maghrib.IsZero() {
+		return false
+	}
+	return tc.MaghribDuration > 0 || !s.Isha.IsZero()
+}
+
+// applyReferenceSchedule applies the reference schedule, with transit time as the
+// common point.
+func applyReferenceSchedule(s, ref Schedule) Schedule {
+	s.Fajr = s.Zuhr.Add(-ref.Zuhr.Sub(ref.Fajr))
+	s.Sunrise = s.Zuhr.Add(-ref.Zuhr.Sub(ref.Sunrise))
+	s.Asr = s.Zuhr.Add(ref.Asr.Sub(ref.Zuhr))
+	s.Maghrib = s.Zuhr.Add(ref.Maghrib.Sub(ref.Zuhr))
+	if !ref.Isha.IsZero() {
+		s.Isha = s.Zuhr.Add(ref.Isha.Sub(ref.Zuhr))
+	}
+	return s
+}
@@ -0,0 +1,108 @@
+package prayer
+
+import (
+	"time"
+)
+
+// DayPredicate is function for choosing the days where an adapter is used, e.g. in
+// `When` adapter. It receives the schedule before adjusted by the adapter.
+type DayPredicate func(s Schedule) bool
+
+// DayShorterThan returns predicate for the days where the duration between sunrise
+// and Maghrib is shorter than the specified duration. If sunrise or Maghrib doesn't
+// exist, the day length is considered zero, the same as in `Mecca` adapter.
+func DayShorterThan(duration time.Duration) DayPredicate {
+	return func(s Schedule) bool {
+		var dayLength time.Duration
+		if !s.Maghrib.IsZero() && !s.Sunrise.IsZero() {
+			dayLength = s.Maghrib.Sub(s.Sunrise)
+		}
+		return dayLength < duration
+	}
+}
+
+// TimeMissing returns predicate for the days where any of the specified times
+// doesn't exist.
+func TimeMissing(prayers ...Prayer) DayPredicate {
+	return func(s Schedule) bool {
+		for _, p := range prayers {
+			if s.Time(p).IsZero() {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Chain is adapter that applies several adapters one after another, where each
+// adapter receives the schedules that adjusted by the previous ones. It's useful
+// combined with `When`, e.g. to use `LocalRelativeEstimation` then fall back to
+// `AlwaysMecca` in the days where sunrise is still missing:
+//
+//	prayer.Chain(
+//		prayer.LocalRelativeEstimation(),
+//		prayer.When(prayer.TimeMissing(prayer.Sunrise), prayer.AlwaysMecca()))
+//
+// Since the combined adapter is not registered, the config that uses it can't be
+// encoded into config file.
+func Chain(adapters ...HighLatitudeAdapter) HighLatitudeAdapter {
+	return func(cfg Config, year int, schedules []Schedule) []Schedule {
+		for _, adapter := range adapters {
+			if adapter != nil {
+				schedules = adapter(cfg, year, schedules)
+			}
+		}
+		return schedules
+	}
+}
+
+// When is adapter that only uses the adjusted schedules from the specified adapter
+// in the days that match the predicate, while the other days are left as they are.
+// The predicate is checked against the schedules before adjusted. For example, to
+// follow Mecca only when the day is shorter than 4 hours:
+//
+//	prayer.When(prayer.DayShorterThan(4*time.Hour), prayer.AlwaysMecca())
+//
+// Do note that the adapter is still calculated for the entire year, so adapter that
+// uses transition period like `Mecca` might lose its transition days.
+func When(predicate DayPredicate, adapter HighLatitudeAdapter) HighLatitudeAdapter {
+	return func(cfg Config, year int, schedules []Schedule) []Schedule {
+		adjusted := adapter(cfg, year, copySchedules(schedules))
+		for i, s := range schedules {
+			if predicate(s) {
+				schedules[i] = adjusted[i]
+			}
+		}
+		return schedules
+	}
+}
+
+// PerPrayer is adapter that uses different adapter for each time, e.g. `AngleBased`
+// for Isha and `NearestDay` for Fajr. Each adapter receives the same schedules, then
+// only the time for its prayer is taken. The times without adapter are left as they
+// are.
+//
+//	prayer.PerPrayer(map[prayer.Prayer]prayer.HighLatitudeAdapter{
+//		prayer.Fajr: prayer.NearestDay(),
+//		prayer.Isha: prayer.AngleBased(),
+//	})
+func PerPrayer(adapters map[Prayer]HighLatitudeAdapter) HighLatitudeAdapter {
+	return func(cfg Config, year int, schedules []Schedule) []Schedule {
+		result := copySchedules(schedules)
+		for p, adapter := range adapters {
+			if adapter == nil {
+				continue
+			}
+
+			adjusted := adapter(cfg, year, copySchedules(schedules))
+			for i := range result {
+				result[i].setTime(p, adjusted[i].Time(p))
+			}
+		}
+		return result
+	}
+}
+
+func copySchedules(schedules []Schedule) []Schedule {
+	return append([]Schedule(nil), schedules...)
+}
@@ -0,0 +1,143 @@
+package prayer
+
+import (
+	"time"
+)
+
+// DiyanetHighLatitude is adapter that follows the high latitude handling of Turkey's
+// Diyanet İşleri Başkanlığı for Europe. In location above 48 degrees latitude, when
+// the Sun doesn't reach the twilight angle, the Fajr and Isha are estimated from the
+// portion of the night at 48 degrees latitude in the same day, so the estimation
+// follows the season. To prevent sudden changes, the schedules around the abnormal
+// periods are smoothed so they don't change more than 5 minutes per day.
+//
+// If sunrise and sunset don't exist, the Fajr and Isha are calculated from transit
+// time using the durations at 48 degrees latitude, so this adapter is usable for area
+// in extreme latitudes (>=65 degrees).
+//
+// With twilight angle deeper than 18.5° (e.g. 20° in `Kemenag`), the Sun might not
+// reach it even at 48 degrees latitude. In those days, the portion of the night is
+// interpolated from the nearest days where the reference times exist.
+//
+// This adapter is intended to be used with `Diyanet` twilight convention and
+// `DiyanetTemkin` corrections, or simply use `Presets.Diyanet`.
+func DiyanetHighLatitude() HighLatitudeAdapter {
+	return highLatDiyanet
+}
+
+// DiyanetTemkin returns the temkin (safety) minutes that used by Turkey's Diyanet
+// İşleri Başkanlığı: -7 minutes for sunrise, +5 for Zuhr, +4 for Asr and +7 for
+// Maghrib.
+func DiyanetTemkin() ScheduleCorrections {
+	return ScheduleCorrections{
+		Sunrise: -7 * time.Minute,
+		Zuhr:    5 * time.Minute,
+		Asr:     4 * time.Minute,
+		Maghrib: 7 * time.Minute,
+	}
+}
+
+func highLatDiyanet(cfg Config, year int, schedules []Schedule) []Schedule {
+	// Get the reference latitude
+	latitude := cfg.Latitude
+	if latitude > 48 {
+		latitude = 48
+	} else if latitude < -48 {
+		latitude = -48
+	} else {
+		return schedules
+	}
+
+	// Calculate schedule for the reference latitude
+	newCfg := Config{
+		Latitude:           latitude,
+		Longitude:          cfg.Longitude,
+		Timezone:           cfg.Timezone,
+		TwilightConvention: cfg.TwilightConvention,
+		AsrConvention:      cfg.AsrConvention}
+	refSchedules, _ := calcNormal(newCfg, year)
+
+	// Calculate the durations and night percentages of Fajr and Isha in the reference
+	// latitude. With deep twilight angle (e.g. 20°), the Sun might not reach it even
+	// at 48 degrees, so in those days the reference is interpolated from the nearest
+	// days where it exists. The abnormal count from calcNormal can't be used to detect
+	// it, since it only checks the fixed 18° twilight.
+	nSchedules := len(refSchedules)
+	fajrDurations := make([]time.Duration, nSchedules)
+	ishaDurations := make([]time.Duration, nSchedules)
+	fajrPercentages := make([]float64, nSchedules)
+	ishaPercentages := make([]float64, nSchedules)
+	hasFajr := make([]bool, nSchedules)
+	hasIsha := make([]bool, nSchedules)
+	for i, rs := range refSchedules {
+		rsDay := rs.Maghrib.Sub(rs.Sunrise).Seconds()
+		rsNight := 24*60*60 - rsDay
+
+		if !rs.Fajr.IsZero() {
+			fajrDurations[i] = rs.Zuhr.Sub(rs.Fajr)
+			fajrPercentages[i] = rs.Sunrise.Sub(rs.Fajr).Seconds() / rsNight
+			hasFajr[i] = true
+		}
+
+		if !rs.Isha.IsZero() {
+			ishaDurations[i] = rs.Isha.Sub(rs.Zuhr)
+			ishaPercentages[i] = rs.Isha.Sub(rs.Maghrib).Seconds() / rsNight
+			hasIsha[i] = true
+		}
+	}
+
+	fajrDurations = interpolateCircular(fajrDurations, hasFajr)
+	ishaDurations = interpolateCircular(ishaDurations, hasIsha)
+	fajrPercentages = interpolateCircular(fajrPercentages, hasFajr)
+	ishaPercentages = interpolateCircular(ishaPercentages, hasIsha)
+
+	// Mark the abnormal days. For the transition, the days where Fajr or Isha is
+	// missing are considered abnormal as well, so the estimated times don't jump from
+	// the calculated ones.
+	flaggedSchedules := make([]Schedule, nSchedules)
+	abnormals := make([]bool, nSchedules)
+	for i, s := range schedules {
+		abnormals[i] = !s.IsNormal
+		flaggedSchedules[i] = s
+		flaggedSchedules[i].IsNormal = s.IsNormal && !s.Fajr.IsZero() && !s.Isha.IsZero()
+	}
+	abnormalSummer, abnormalWinter := extractAbnormalSchedules(flaggedSchedules)
+
+	// Estimate Fajr and Isha in abnormal days. With deep twilight angle, the times
+	// might be missing in normal days as well, so in those days only the missing time
+	// is estimated. If the reference doesn't exist at all, the time is left as it is.
+	for i, s := range schedules {
+		estimateFajr := abnormals[i] || s.Fajr.IsZero()
+		estimateIsha := abnormals[i] || s.Isha.IsZero()
+		if !estimateFajr && !estimateIsha {
+			continue
+		}
+
+		// If sunrise and sunset don't exist, use transit as the common point
+		if s.Sunrise.IsZero() || s.Maghrib.IsZero() {
+			if estimateFajr && fajrDurations[i] != 0 {
+				schedules[i].Fajr = s.Zuhr.Add(-fajrDurations[i])
+			}
+			if estimateIsha && ishaDurations[i] != 0 {
+				schedules[i].Isha = s.Zuhr.Add(ishaDurations[i])
+			}
+			continue
+		}
+
+		// Apply the night percentage in reference latitude to the current night
+		sDay := s.Maghrib.Sub(s.Sunrise).Seconds()
+		sNight := 24*60*60 - sDay
+		if estimateFajr && fajrPercentages[i] != 0 {
+			fajrDuration := time.Duration(sNight * fajrPercentages[i] * float64(time.Second))
+			schedules[i].Fajr = s.Sunrise.Add(-fajrDuration)
+		}
+		if estimateIsha && ishaPercentages[i] != 0 {
+			ishaDuration := time.Duration(sNight * ishaPercentages[i] * float64(time.Second))
+			schedules[i].Isha = s.Maghrib.Add(ishaDuration)
+		}
+	}
+
+	schedules = applyLocalRelativeTransition(schedules, abnormalSummer)
+	schedules = applyLocalRelativeTransition(schedules, abnormalWinter)
+	return schedules
+}
@@ -0,0 +1,131 @@
+package prayer
+
+import (
+	"time"
+)
+
+// FastingCapConfig is configuration for `FastingCap` adapter.
+type FastingCapConfig struct {
+	// MaxDuration is the maximum fasting duration, i.e. the duration between Fajr
+	// and Maghrib. If not specified, it will use 18 hours. This field is ignored if
+	// `RelativeToMecca` is enabled.
+	MaxDuration time.Duration
+
+	// RelativeToMecca specify whether the maximum fasting duration follows the
+	// fasting duration in Mecca on the same day, plus `MeccaMargin`.
+	RelativeToMecca bool
+
+	// MeccaMargin is the duration that added to the fasting duration in Mecca when
+	// `RelativeToMecca` is enabled.
+	MeccaMargin time.Duration
+}
+
+// FastingCap is adapter based on fatwas for northern Europe which allow to cap the
+// fasting duration, e.g. at 18 or 19 hours, or at the fasting duration in Mecca plus
+// some margin. When the duration between Fajr and Maghrib exceeds the cap, or when
+// Fajr doesn't exist because the Sun never reaches the twilight angle, Fajr is moved
+// later so the fasting duration equals the cap. However, Fajr will never be moved
+// after sunrise. Since Imsak in `CalculateRamadan` is derived from Fajr, it will be
+// moved as well.
+//
+// To prevent sudden schedule changes, Fajr around the capped periods is smoothed
+// using the same transition as `Mecca` adapter, without ever moving it earlier than
+// the calculated Fajr.
+//
+// This adapter only adjusts Fajr and require sunrise and sunset time. Therefore it's
+// not suitable for area in extreme latitude (>=65 degrees).
+func FastingCap(fcCfg FastingCapConfig) HighLatitudeAdapter {
+	// Use the registered caps when possible, so the config can be encoded
+	if !fcCfg.RelativeToMecca {
+		switch {
+		case fcCfg.MaxDuration <= 0, fcCfg.MaxDuration == 18*time.Hour:
+			return highLatFastingCap18h
+		case fcCfg.MaxDuration == 19*time.Hour:
+			return highLatFastingCap19h
+		}
+	}
+
+	return func(cfg Config, year int, schedules []Schedule) []Schedule {
+		return applyFastingCap(cfg, year, schedules, fcCfg)
+	}
+}
+
+func highLatFastingCap18h(cfg Config, year int, schedules []Schedule) []Schedule {
+	return applyFastingCap(cfg, year, schedules, FastingCapConfig{MaxDuration: 18 * time.Hour})
+}
+
+func highLatFastingCap19h(cfg Config, year int, schedules []Schedule) []Schedule {
+	return applyFastingCap(cfg, year, schedules, FastingCapConfig{MaxDuration: 19 * time.Hour})
+}
+
+func applyFastingCap(cfg Config, year int, schedules []Schedule, fcCfg FastingCapConfig) []Schedule {
+	// This conventions only works if daytime exists (in other words, sunrise
+	// and Maghrib must exist). So if there are days where those time don't
+	// exist, stop and just return the schedule as it is.
+	for _, s := range schedules {
+		if s.Sunrise.IsZero() || s.Maghrib.IsZero() {
+			return schedules
+		}
+	}
+
+	// Apply default config
+	if fcCfg.MaxDuration <= 0 {
+		fcCfg.MaxDuration = 18 * time.Hour
+	}
+
+	// Calculate schedule for Mecca
+	var meccaSchedules []Schedule
+	if fcCfg.RelativeToMecca {
+		meccaCfg := Config{
+			Latitude:           kaabaLatitude,
+			Longitude:          kaabaLongitude,
+			Timezone:           meccaTimezone(),
+			TwilightConvention: cfg.TwilightConvention,
+			AsrConvention:      cfg.AsrConvention}
+		meccaSchedules, _ = calcNormal(meccaCfg, year)
+	}
+
+	// Move Fajr in the days where fasting duration exceeds the cap. Here the capped
+	// days are marked as abnormal, so they can be used for creating transition.
+	nSchedules := len(schedules)
+	fajrTimes := make([]time.Time, nSchedules)
+	cappedSchedules := make([]Schedule, nSchedules)
+	for i, s := range schedules {
+		maxDuration := fcCfg.MaxDuration
+		if fcCfg.RelativeToMecca && i < len(meccaSchedules) {
+			ms := meccaSchedules[i]
+			maxDuration = ms.Maghrib.Sub(ms.Fajr) + fcCfg.MeccaMargin
+		}
+
+		fajr := s.Fajr
+		capped := fajr.IsZero() || s.Maghrib.Sub(fajr) > maxDuration
+		if capped {
+			fajr = s.Maghrib.Add(-maxDuration)
+			if fajr.After(s.Sunrise) {
+				fajr = s.Sunrise
+			}
+		}
+
+		fajrTimes[i] = fajr
+		cappedSchedules[i] = s
+		cappedSchedules[i].IsNormal = !capped
+	}
+
+	// Create transition, but make sure Fajr is never earlier than the calculated one
+	abnormalSummer, abnormalWinter := extractAbnormalSchedules(cappedSchedules)
+	if !abnormalSummer.IsEmpty() || !abnormalWinter.IsEmpty() {
+		fajrTimes = createMeccaTransition(fajrTimes, abnormalSummer, abnormalWinter)
+	}
+
+	for i, s := range schedules {
+		if fajrTimes[i].After(s.Sunrise) {
+			fajrTimes[i] = s.Sunrise
+		}
+
+		if s.Fajr.IsZero() || fajrTimes[i].After(s.Fajr) {
+			schedules[i].Fajr = fajrTimes[i]
+		}
+	}
+
+	return schedules
+}
@@ -1,9 +1,23 @@
 package prayer
 
 import (
+	"sort"
 	"time"
 )
 
+// LocalRelativeEstimationConfig is configuration for `LocalRelativeEstimationWith`.
+type LocalRelativeEstimationConfig struct {
+	// NearestDays is the number of normal days nearest to each abnormal period that
+	// used for calculating the average percentage. If zero, every normal days in the
+	// year will be used.
+	NearestDays int
+
+	// WeightByDistance specify whether the percentage of each normal day is weighted
+	// by the inverse of its distance (in days) to the abnormal period, so the nearer
+	// days have more influence.
+	WeightByDistance bool
+}
+
 // LocalRelativeEstimation is adapter using method that created by cooperation between
 // Fiqh Council of Muslim World League and Islamic Crescents' Observation Project (ICOP).
 // In short, this method uses average percentage to calculate Fajr and Isha time for
@@ -17,15 +31,34 @@ func LocalRelativeEstimation() HighLatitudeAdapter {
 	return highLatLocalRelativeEstimation
 }
 
+// LocalRelativeEstimationWith is the same as `LocalRelativeEstimation`, but the
+// average percentage can be calculated only from the normal days nearest to each
+// abnormal period, or weighted by their distance. This way the normal days in
+// mid-winter don't affect the estimation in summer, and vice versa.
+func LocalRelativeEstimationWith(lreCfg LocalRelativeEstimationConfig) HighLatitudeAdapter {
+	// Use the registered adapter when possible, so the config can be encoded
+	if lreCfg == (LocalRelativeEstimationConfig{}) {
+		return highLatLocalRelativeEstimation
+	}
+
+	return func(cfg Config, year int, schedules []Schedule) []Schedule {
+		return applyLocalRelativeEstimation(schedules, lreCfg)
+	}
+}
+
 func highLatLocalRelativeEstimation(cfg Config, year int, schedules []Schedule) []Schedule {
-	var (
-		nFajrSample     int
-		nIshaSample     int
-		sumFajrPercents float64
-		sumIshaPercents float64
-	)
-
-	for _, s := range schedules {
+	return applyLocalRelativeEstimation(schedules, LocalRelativeEstimationConfig{})
+}
+
+// localRelativeSample is the night percentage of Fajr or Isha in a normal day.
+type localRelativeSample struct {
+	Index      int
+	Percentage float64
+}
+
+func applyLocalRelativeEstimation(schedules []Schedule, lreCfg LocalRelativeEstimationConfig) []Schedule {
+	var fajrSamples, ishaSamples []localRelativeSample
+	for i, s := range schedules {
 		// This conventions only works if daytime exists (in other words, sunrise
 		// and Maghrib must exist). So if there are days where those time don't
 		// exist, stop and just return the schedule as it is.
@@ -43,39 +76,46 @@ func highLatLocalRelativeEstimation(cfg Config, year int, schedules []Schedule)
 			// Calculate Fajr percentage
 			if !s.Fajr.IsZero() {
 				fajrDuration := s.Sunrise.Sub(s.Fajr).Seconds()
-				sumFajrPercents += fajrDuration / nightDuration
-				nFajrSample++
+				fajrSamples = append(fajrSamples, localRelativeSample{i, fajrDuration / nightDuration})
 			}
 
 			// Calculate Isha percentage
 			if !s.Isha.IsZero() {
 				ishaDuration := s.Isha.Sub(s.Maghrib).Seconds()
-				sumIshaPercents += ishaDuration / nightDuration
-				nIshaSample++
+				ishaSamples = append(ishaSamples, localRelativeSample{i, ishaDuration / nightDuration})
 			}
 		}
 	}
 
-	// Calculate average percentage
-	avgFajrPercents := sumFajrPercents / float64(nFajrSample)
-	avgIshaPercents := sumIshaPercents / float64(nIshaSample)
-
 	// Extract abnormal schedules
 	abnormalSummer, abnormalWinter := extractAbnormalSchedules(schedules)
 
 	// Fix Fajr and Isha times in abnormal days
 	for _, as := range []abnormalRange{abnormalSummer, abnormalWinter} {
+		if as.IsEmpty() {
+			continue
+		}
+
+		// Calculate average percentage. If there are no samples, the time is left as
+		// it is to prevent division by zero.
+		avgFajrPercents, fajrOK := averageLocalRelative(fajrSamples, as, len(schedules), lreCfg)
+		avgIshaPercents, ishaOK := averageLocalRelative(ishaSamples, as, len(schedules), lreCfg)
+
 		for _, i := range as.Indexes {
 			s := schedules[i]
 			dayDuration := s.Maghrib.Sub(s.Sunrise).Seconds()
 			nightDuration := 24*60*60 - dayDuration
 
 			if !s.IsNormal {
-				fajrDuration := nightDuration * avgFajrPercents * float64(time.Second)
-				schedules[i].Fajr = s.Sunrise.Add(-time.Duration(fajrDuration))
+				if fajrOK {
+					fajrDuration := nightDuration * avgFajrPercents * float64(time.Second)
+					schedules[i].Fajr = s.Sunrise.Add(-time.Duration(fajrDuration))
+				}
 
-				ishaDuration := nightDuration * avgIshaPercents * float64(time.Second)
-				schedules[i].Isha = s.Maghrib.Add(time.Duration(ishaDuration))
+				if ishaOK {
+					ishaDuration := nightDuration * avgIshaPercents * float64(time.Second)
+					schedules[i].Isha = s.Maghrib.Add(time.Duration(ishaDuration))
+				}
 			}
 		}
 	}
@@ -85,6 +125,63 @@ func highLatLocalRelativeEstimation(cfg Config, year int, schedules []Schedule)
 	return schedules
 }
 
+// averageLocalRelative returns the average percentage of the samples for the
+// abnormal period. It returns false if there are no samples to average.
+func averageLocalRelative(samples []localRelativeSample, period abnormalRange, nDays int, lreCfg LocalRelativeEstimationConfig) (float64, bool) {
+	if len(samples) == 0 {
+		return 0, false
+	}
+
+	// Without window or weight, use every samples equally
+	if lreCfg.NearestDays <= 0 && !lreCfg.WeightByDistance {
+		var sum float64
+		for _, sample := range samples {
+			sum += sample.Percentage
+		}
+		return sum / float64(len(samples)), true
+	}
+
+	// Calculate the distance of each sample to the period, circularly
+	first, _ := firstSliceItem(period.Indexes)
+	last, _ := lastSliceItem(period.Indexes)
+	distances := make([]int, len(samples))
+	for i, sample := range samples {
+		toFirst := ((first-sample.Index)%nDays + nDays) % nDays
+		fromLast := ((sample.Index-last)%nDays + nDays) % nDays
+		distances[i] = toFirst
+		if fromLast < toFirst {
+			distances[i] = fromLast
+		}
+	}
+
+	// Pick the nearest samples
+	order := make([]int, len(samples))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return distances[order[a]] < distances[order[b]]
+	})
+
+	if lreCfg.NearestDays > 0 && len(order) > lreCfg.NearestDays {
+		order = order[:lreCfg.NearestDays]
+	}
+
+	// Calculate the average
+	var sum, sumWeight float64
+	for _, idx := range order {
+		weight := 1.0
+		if lreCfg.WeightByDistance && distances[idx] > 0 {
+			weight = 1 / float64(distances[idx])
+		}
+		sum += samples[idx].Percentage * weight
+		sumWeight += weight
+	}
+
+	return sum / sumWeight, true
+}
+
 func applyLocalRelativeTransition(schedules []Schedule, abnormalPeriod abnormalRange) []Schedule {
 	// If there are no abnormality, return as it is
 	if abnormalPeriod.IsEmpty() {
@@ -142,6 +239,11 @@ func applyLocalRelativeTransition(schedules []Schedule, abnormalPeriod abnormalR
 }
 
 func applyLocalRelativeTransitionTime(reference, today time.Time) (time.Time, bool) {
+	// If the time is not estimated (e.g. there are no samples), leave it as it is
+	if reference.IsZero() || today.IsZero() {
+		return today, false
+	}
+
 	// Calculate diff between today and reference
 	var diff time.Duration
 	var referenceIsForward bool
@@ -0,0 +1,93 @@
+package prayer
+
+import (
+	"math"
+	"time"
+
+	"github.com/hablullah/go-sampa"
+)
+
+// MaxDepression is adapter where Fajr and Isha are put at the moment of maximum
+// solar depression (solar midnight), i.e. the lowest point of the Sun in the night,
+// for the days when the Sun never reaches the twilight angle. Fajr uses the lowest
+// point in the night before the day, while Isha uses the lowest point in the night
+// after the day.
+//
+// Some scholars only allow this when the maximum depression is deep enough, so it
+// can be limited using minDepression in degrees, e.g. 12 for the nautical twilight.
+// If the Sun doesn't reach minDepression, Fajr and Isha are left as they are. Use 0
+// (or any non positive value) to apply it in every night, including when the Sun
+// never sets.
+//
+// Since the lowest point is calculated directly from the Sun's position, this adapter
+// doesn't require the sunrise and sunset to be exist in a day, so it's usable for
+// area in extreme latitudes (>=65 degrees).
+func MaxDepression(minDepression float64) HighLatitudeAdapter {
+	// Use the registered adapter when possible, so the config can be encoded
+	if minDepression <= 0 {
+		return highLatMaxDepression
+	}
+
+	return func(cfg Config, year int, schedules []Schedule) []Schedule {
+		return applyMaxDepression(cfg, schedules, minDepression)
+	}
+}
+
+func highLatMaxDepression(cfg Config, year int, schedules []Schedule) []Schedule {
+	return applyMaxDepression(cfg, schedules, 0)
+}
+
+func applyMaxDepression(cfg Config, schedules []Schedule, minDepression float64) []Schedule {
+	location := sampa.Location{
+		Latitude:  cfg.Latitude,
+		Longitude: cfg.Longitude,
+		Elevation: cfg.Elevation,
+	}
+
+	nSchedules := len(schedules)
+	for i, s := range schedules {
+		if !s.Fajr.IsZero() && !s.Isha.IsZero() {
+			continue
+		}
+
+		// Get transit in the previous and next day. For the first and last day, it's
+		// approximated as 24 hours apart.
+		prevTransit := s.Zuhr.Add(-24 * time.Hour)
+		if i > 0 {
+			prevTransit = schedules[i-1].Zuhr
+		}
+
+		nextTransit := s.Zuhr.Add(24 * time.Hour)
+		if i < nSchedules-1 {
+			nextTransit = schedules[i+1].Zuhr
+		}
+
+		if s.Fajr.IsZero() {
+			midnight, depression := findSolarMidnight(location, prevTransit, s.Zuhr)
+			if minDepression <= 0 || depression >= minDepression {
+				schedules[i].Fajr = midnight
+			}
+		}
+
+		if s.Isha.IsZero() {
+			midnight, depression := findSolarMidnight(location, s.Zuhr, nextTransit)
+			if minDepression <= 0 || depression >= minDepression {
+				schedules[i].Isha = midnight
+			}
+		}
+	}
+
+	return schedules
+}
+
+// findSolarMidnight returns the time when the Sun reaches its lowest point between
+// two consecutive transits, along with the solar depression (negative of elevation)
+// in degrees at that time. The lowest point is the lower culmination, i.e. in the
+// middle between the transits. The depression is calculated from the declination
+// instead of the elevation, since the latter is affected by atmospheric refraction.
+func findSolarMidnight(location sampa.Location, transit, nextTransit time.Time) (time.Time, float64) {
+	midnight := transit.Add(nextTransit.Sub(transit) / 2).Round(time.Second)
+	pos, _ := sampa.GetSunPosition(midnight, location, nil)
+	depression := 90 - math.Abs(location.Latitude+pos.TopocentricDeclination)
+	return midnight, depression
+}
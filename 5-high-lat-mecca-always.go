@@ -1,9 +1,5 @@
 package prayer
 
-import (
-	"time"
-)
-
 // AlwaysMecca is similar with `Mecca`, except it will be applied every day and not
 // only on the "abnormal" days.
 //
@@ -15,11 +11,10 @@ func AlwaysMecca() HighLatitudeAdapter {
 
 func highLatAlwaysMecca(cfg Config, year int, schedules []Schedule) []Schedule {
 	// Calculate schedule for Mecca
-	meccaTz, _ := time.LoadLocation("Asia/Riyadh")
 	meccaCfg := Config{
-		Latitude:           21.425506007708996,
-		Longitude:          39.8254579358597,
-		Timezone:           meccaTz,
+		Latitude:           kaabaLatitude,
+		Longitude:          kaabaLongitude,
+		Timezone:           meccaTimezone(),
 		TwilightConvention: cfg.TwilightConvention,
 		AsrConvention:      cfg.AsrConvention}
 	meccaSchedules, _ := calcNormal(meccaCfg, year)
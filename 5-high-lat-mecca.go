@@ -38,11 +38,10 @@ func highLatMecca(cfg Config, year int, schedules []Schedule) []Schedule {
 	abnormalSummer, abnormalWinter := extractAbnormalSchedules(schedules)
 
 	// Calculate schedule for Mecca
-	meccaTz, _ := time.LoadLocation("Asia/Riyadh")
 	meccaCfg := Config{
-		Latitude:           21.425506007708996,
-		Longitude:          39.8254579358597,
-		Timezone:           meccaTz,
+		Latitude:           kaabaLatitude,
+		Longitude:          kaabaLongitude,
+		Timezone:           meccaTimezone(),
 		TwilightConvention: cfg.TwilightConvention,
 		AsrConvention:      cfg.AsrConvention}
 	meccaSchedules, _ := calcNormal(meccaCfg, year)
@@ -96,6 +95,29 @@ func applyMeccaTransition(schedules []Schedule, abnormalSummer, abnormalWinter a
 		ishaTimes[idx] = s.Isha
 	}
 
+	// Apply transition times
+	fajrTimes = createMeccaTransition(fajrTimes, abnormalSummer, abnormalWinter)
+	sunriseTimes = createMeccaTransition(sunriseTimes, abnormalSummer, abnormalWinter)
+	asrTimes = createMeccaTransition(asrTimes, abnormalSummer, abnormalWinter)
+	maghribTimes = createMeccaTransition(maghribTimes, abnormalSummer, abnormalWinter)
+	ishaTimes = createMeccaTransition(ishaTimes, abnormalSummer, abnormalWinter)
+
+	// Put back times to schedule
+	for idx, s := range schedules {
+		s.Fajr = fajrTimes[idx]
+		s.Sunrise = sunriseTimes[idx]
+		s.Asr = asrTimes[idx]
+		s.Maghrib = maghribTimes[idx]
+		s.Isha = ishaTimes[idx]
+		schedules[idx] = s
+	}
+
+	return schedules
+}
+
+// createMeccaTransition creates transition times before and after the abnormal
+// periods, so the times don't change suddenly.
+func createMeccaTransition(times []time.Time, abnormalSummer, abnormalWinter abnormalRange) []time.Time {
 	// Check if there is only one abnormal period
 	onlySummer := abnormalWinter.IsEmpty() && !abnormalSummer.IsEmpty()
 	onlyWinter := abnormalSummer.IsEmpty() && !abnormalWinter.IsEmpty()
@@ -107,20 +129,12 @@ func applyMeccaTransition(schedules []Schedule, abnormalSummer, abnormalWinter a
 		}
 
 		// Calculate transition duration from leftover days
-		leftoverDays := len(schedules) - len(abnormalPeriod.Indexes)
+		leftoverDays := len(times) - len(abnormalPeriod.Indexes)
 		nTransitionDays := leftoverDays / 2
 
 		// Apply transition times
-		fajrTimes = createMeccaPreTransition(fajrTimes, abnormalPeriod, nTransitionDays)
-		fajrTimes = createMeccaPostTransition(fajrTimes, abnormalPeriod, nTransitionDays)
-		sunriseTimes = createMeccaPreTransition(sunriseTimes, abnormalPeriod, nTransitionDays)
-		sunriseTimes = createMeccaPostTransition(sunriseTimes, abnormalPeriod, nTransitionDays)
-		asrTimes = createMeccaPreTransition(asrTimes, abnormalPeriod, nTransitionDays)
-		asrTimes = createMeccaPostTransition(asrTimes, abnormalPeriod, nTransitionDays)
-		maghribTimes = createMeccaPreTransition(maghribTimes, abnormalPeriod, nTransitionDays)
-		maghribTimes = createMeccaPostTransition(maghribTimes, abnormalPeriod, nTransitionDays)
-		ishaTimes = createMeccaPreTransition(ishaTimes, abnormalPeriod, nTransitionDays)
-		ishaTimes = createMeccaPostTransition(ishaTimes, abnormalPeriod, nTransitionDays)
+		times = createMeccaPreTransition(times, abnormalPeriod, nTransitionDays)
+		times = createMeccaPostTransition(times, abnormalPeriod, nTransitionDays)
 	} else if !abnormalSummer.IsEmpty() && !abnormalWinter.IsEmpty() {
 		// Fetch indexes
 		summerIdxStart, _ := firstSliceItem(abnormalSummer.Indexes)
@@ -142,41 +156,15 @@ func applyMeccaTransition(schedules []Schedule, abnormalSummer, abnormalWinter a
 		postSummerTransitionDays := summerWinterTransitionDays
 
 		// Create winter transition
-		fajrTimes = createMeccaPreTransition(fajrTimes, abnormalWinter, preWinterTransitionDays)
-		fajrTimes = createMeccaPostTransition(fajrTimes, abnormalWinter, postWinterTransitionDays)
-		sunriseTimes = createMeccaPreTransition(sunriseTimes, abnormalWinter, preWinterTransitionDays)
-		sunriseTimes = createMeccaPostTransition(sunriseTimes, abnormalWinter, postWinterTransitionDays)
-		asrTimes = createMeccaPreTransition(asrTimes, abnormalWinter, preWinterTransitionDays)
-		asrTimes = createMeccaPostTransition(asrTimes, abnormalWinter, postWinterTransitionDays)
-		maghribTimes = createMeccaPreTransition(maghribTimes, abnormalWinter, preWinterTransitionDays)
-		maghribTimes = createMeccaPostTransition(maghribTimes, abnormalWinter, postWinterTransitionDays)
-		ishaTimes = createMeccaPreTransition(ishaTimes, abnormalWinter, preWinterTransitionDays)
-		ishaTimes = createMeccaPostTransition(ishaTimes, abnormalWinter, postWinterTransitionDays)
+		times = createMeccaPreTransition(times, abnormalWinter, preWinterTransitionDays)
+		times = createMeccaPostTransition(times, abnormalWinter, postWinterTransitionDays)
 
 		// Create summer transition
-		fajrTimes = createMeccaPreTransition(fajrTimes, abnormalSummer, preSummerTransitionDays)
-		fajrTimes = createMeccaPostTransition(fajrTimes, abnormalSummer, postSummerTransitionDays)
-		sunriseTimes = createMeccaPreTransition(sunriseTimes, abnormalSummer, preSummerTransitionDays)
-		sunriseTimes = createMeccaPostTransition(sunriseTimes, abnormalSummer, postSummerTransitionDays)
-		asrTimes = createMeccaPreTransition(asrTimes, abnormalSummer, preSummerTransitionDays)
-		asrTimes = createMeccaPostTransition(asrTimes, abnormalSummer, postSummerTransitionDays)
-		maghribTimes = createMeccaPreTransition(maghribTimes, abnormalSummer, preSummerTransitionDays)
-		maghribTimes = createMeccaPostTransition(maghribTimes, abnormalSummer, postSummerTransitionDays)
-		ishaTimes = createMeccaPreTransition(ishaTimes, abnormalSummer, preSummerTransitionDays)
-		ishaTimes = createMeccaPostTransition(ishaTimes, abnormalSummer, postSummerTransitionDays)
+		times = createMeccaPreTransition(times, abnormalSummer, preSummerTransitionDays)
+		times = createMeccaPostTransition(times, abnormalSummer, postSummerTransitionDays)
 	}
 
-	// Put back times to schedule
-	for idx, s := range schedules {
-		s.Fajr = fajrTimes[idx]
-		s.Sunrise = sunriseTimes[idx]
-		s.Asr = asrTimes[idx]
-		s.Maghrib = maghribTimes[idx]
-		s.Isha = ishaTimes[idx]
-		schedules[idx] = s
-	}
-
-	return schedules
+	return times
 }
 
 func createMeccaPreTransition(times []time.Time, abnormalPeriod abnormalRange, nTransitionDays int) []time.Time {
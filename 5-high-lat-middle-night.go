@@ -9,8 +9,9 @@ import (
 // Fajr and Isha in this method are assumed to be at mid-night during the abnormal
 // periods.
 //
-// This adapter depends on sunrise and sunset time, so it might not be suitable for
-// area in extreme latitudes (>=65 degrees).
+// In extreme latitudes (>=65 degrees) where the Sun doesn't rise or set, this adapter
+// uses virtual night whose sunrise and sunset are interpolated from the last and the
+// first days that have them, relative to the transit time.
 //
 // Reference: http://praytimes.org/calculation
 func MiddleNight() HighLatitudeAdapter {
@@ -18,17 +19,21 @@ func MiddleNight() HighLatitudeAdapter {
 }
 
 func highLatMiddleNight(_ Config, _ int, schedules []Schedule) []Schedule {
+	// In polar day or night, use the virtual sunrise and Maghrib
+	sunrises, maghribs := virtualSunTimes(schedules)
+
 	for i, s := range schedules {
 		// Middle night require Sunrise and Maghrib, and only done if Fajr or Isha missing
-		if !s.Sunrise.IsZero() && !s.Maghrib.IsZero() && (s.Fajr.IsZero() || s.Isha.IsZero()) {
+		sunrise, maghrib := sunrises[i], maghribs[i]
+		if !sunrise.IsZero() && !maghrib.IsZero() && (s.Fajr.IsZero() || s.Isha.IsZero()) {
 			// Calculate night duration
-			dayDuration := s.Maghrib.Sub(s.Sunrise).Seconds()
+			dayDuration := maghrib.Sub(sunrise).Seconds()
 			nightDuration := float64(24*60*60) - dayDuration
 
 			// Calculate Fajr and Isha time
 			halfDuration := time.Duration(nightDuration * 0.5 * float64(time.Second))
-			schedules[i].Fajr = s.Sunrise.Add(-halfDuration)
-			schedules[i].Isha = s.Maghrib.Add(halfDuration)
+			schedules[i].Fajr = sunrise.Add(-halfDuration)
+			schedules[i].Isha = maghrib.Add(halfDuration)
 		}
 	}
 
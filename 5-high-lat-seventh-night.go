@@ -8,8 +8,9 @@ import (
 // Isha starts when the first seventh part ends, and Fajr starts when the last seventh
 // part starts.
 //
-// This adapter depends on sunrise and sunset time, so it might not be suitable for
-// area in extreme latitudes (>=65 degrees).
+// In extreme latitudes (>=65 degrees) where the Sun doesn't rise or set, this adapter
+// uses virtual night whose sunrise and sunset are interpolated from the last and the
+// first days that have them, relative to the transit time.
 //
 // Reference: http://praytimes.org/calculation
 func OneSeventhNight() HighLatitudeAdapter {
@@ -17,17 +18,21 @@ func OneSeventhNight() HighLatitudeAdapter {
 }
 
 func highLatOneSeventhNight(_ Config, _ int, schedules []Schedule) []Schedule {
+	// In polar day or night, use the virtual sunrise and Maghrib
+	sunrises, maghribs := virtualSunTimes(schedules)
+
 	for i, s := range schedules {
 		// Seventh night require Sunrise and Maghrib, and only done if Fajr or Isha missing
-		if !s.Sunrise.IsZero() && !s.Maghrib.IsZero() && (s.Fajr.IsZero() || s.Isha.IsZero()) {
+		sunrise, maghrib := sunrises[i], maghribs[i]
+		if !sunrise.IsZero() && !maghrib.IsZero() && (s.Fajr.IsZero() || s.Isha.IsZero()) {
 			// Calculate night duration
-			dayDuration := s.Maghrib.Sub(s.Sunrise).Seconds()
+			dayDuration := maghrib.Sub(sunrise).Seconds()
 			nightDuration := float64(24*60*60) - dayDuration
 
 			// Calculate Fajr and Isha time
 			seventhDuration := time.Duration(nightDuration / 7 * float64(time.Second))
-			schedules[i].Fajr = s.Sunrise.Add(-seventhDuration)
-			schedules[i].Isha = s.Maghrib.Add(seventhDuration)
+			schedules[i].Fajr = sunrise.Add(-seventhDuration)
+			schedules[i].Isha = maghrib.Add(seventhDuration)
 		}
 	}
 
@@ -0,0 +1,427 @@
+package prayer_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+	"github.com/hablullah/go-prayer/internal/datatest"
+)
+
+func TestFastingCap(t *testing.T) {
+	td := datatest.London
+	cfg := prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.MWL(),
+		PreciseToSeconds:   true,
+	}
+
+	original, err := prayer.Calculate(cfg, 2023)
+	assertNil(t, err, "original schedules error")
+
+	for _, fcCfg := range []prayer.FastingCapConfig{
+		{MaxDuration: 18 * time.Hour},
+		{RelativeToMecca: true, MeccaMargin: 3 * time.Hour},
+	} {
+		cfg.HighLatitudeAdapter = prayer.FastingCap(fcCfg)
+		schedules, err := prayer.Calculate(cfg, 2023)
+		assertNil(t, err, "capped schedules error")
+
+		for i, s := range schedules {
+			o := original[i]
+			msg := fmt.Sprintf("capped fajr %s with %+v", s.Date, fcCfg)
+			assertEqual(t, false, s.Fajr.IsZero(), msg+" is empty")
+			assertEqual(t, false, s.Fajr.After(s.Sunrise), msg+" after sunrise")
+			assertEqual(t, false, !o.Fajr.IsZero() && s.Fajr.Before(o.Fajr), msg+" earlier than calculated")
+			if !fcCfg.RelativeToMecca {
+				assertLTE(t, s.Maghrib.Sub(s.Fajr), fcCfg.MaxDuration, msg+" exceeds the cap")
+			}
+
+			// Fajr moves smoothly relative to transit, so it's not affected by DST
+			if i > 0 {
+				prev := schedules[i-1]
+				diff := s.Zuhr.Sub(s.Fajr) - prev.Zuhr.Sub(prev.Fajr)
+				if diff < 0 {
+					diff = -diff
+				}
+				assertLTE(t, diff, 5*time.Minute, msg+" jumps")
+			}
+		}
+	}
+}
+
+func TestMaxDepression(t *testing.T) {
+	td := datatest.Tromso
+	cfg := prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.AstronomicalTwilight(),
+		PreciseToSeconds:   true,
+	}
+
+	original, err := prayer.Calculate(cfg, 2023)
+	assertNil(t, err, "original schedules error")
+
+	cfg.HighLatitudeAdapter = prayer.MaxDepression(0)
+	schedules, err := prayer.Calculate(cfg, 2023)
+	assertNil(t, err, "adapted schedules error")
+
+	for i, s := range schedules {
+		o := original[i]
+		msg := fmt.Sprintf("max depression %s", s.Date)
+		assertEqual(t, false, s.Fajr.IsZero(), msg+" fajr is empty")
+		assertEqual(t, false, s.Isha.IsZero(), msg+" isha is empty")
+
+		// The lowest point is around 12 hours from transit
+		if o.Fajr.IsZero() {
+			diff := s.Zuhr.Sub(s.Fajr) - 12*time.Hour
+			assertLTE(t, diff.Abs(), 20*time.Minute, msg+" fajr is not at solar midnight")
+		} else {
+			assertEqual(t, o.Fajr, s.Fajr, msg+" normal fajr is changed")
+		}
+
+		if o.Isha.IsZero() {
+			diff := s.Isha.Sub(s.Zuhr) - 12*time.Hour
+			assertLTE(t, diff.Abs(), 20*time.Minute, msg+" isha is not at solar midnight")
+		}
+	}
+
+	// In London the Sun is at most around 15° below horizon in summer
+	td = datatest.London
+	cfg.Latitude, cfg.Longitude, cfg.Timezone = td.Latitude, td.Longitude, td.Timezone
+	for minDepression, expectEmpty := range map[float64]bool{12: false, 16: true} {
+		cfg.HighLatitudeAdapter = prayer.MaxDepression(minDepression)
+		schedules, err = prayer.Calculate(cfg, 2023)
+		assertNil(t, err, "london schedules error")
+
+		summer := schedules[172]
+		msg := fmt.Sprintf("london fajr %s with minimum depression %v", summer.Date, minDepression)
+		assertEqual(t, expectEmpty, summer.Fajr.IsZero(), msg)
+	}
+}
+
+func TestAngleReduction(t *testing.T) {
+	td := datatest.Tromso
+	cfg := prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.AstronomicalTwilight(),
+		PreciseToSeconds:   true,
+	}
+
+	original, err := prayer.Calculate(cfg, 2023)
+	assertNil(t, err, "original schedules error")
+
+	cfg.HighLatitudeAdapter = prayer.AngleReduction(prayer.AngleReductionConfig{})
+	schedules, err := prayer.Calculate(cfg, 2023)
+	assertNil(t, err, "adapted schedules error")
+
+	var nOriginal, nReduced int
+	for i, s := range schedules {
+		o := original[i]
+		msg := fmt.Sprintf("angle reduction %s", s.Date)
+		if !o.Fajr.IsZero() {
+			nOriginal++
+			assertEqual(t, false, s.Fajr.Before(o.Fajr), msg+" normal fajr is earlier")
+		}
+
+		if s.Fajr.IsZero() {
+			continue
+		}
+		nReduced++
+
+		if !s.Sunrise.IsZero() {
+			assertEqual(t, true, s.Fajr.Before(s.Sunrise), msg+" fajr after sunrise")
+		}
+
+		// Fajr moves smoothly relative to transit
+		prev := schedules[(i+len(schedules)-1)%len(schedules)]
+		if !prev.Fajr.IsZero() {
+			diff := s.Zuhr.Sub(s.Fajr) - prev.Zuhr.Sub(prev.Fajr)
+			assertLTE(t, diff.Abs(), 10*time.Minute, msg+" fajr jumps")
+		}
+	}
+
+	// Some days get their Fajr back, but not in midnight sun period where the Sun
+	// doesn't reach 12°
+	assertLTE(t, nOriginal+1, nReduced, "angle reduction doesn't fill any fajr")
+	assertEqual(t, true, schedules[172].Fajr.IsZero(), "angle reduction fills fajr in midnight sun")
+}
+
+func TestAqrabAlBilad(t *testing.T) {
+	td := datatest.Tromso
+	cfg := prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.AstronomicalTwilight(),
+		PreciseToSeconds:   true,
+	}
+
+	original, err := prayer.Calculate(cfg, 2023)
+	assertNil(t, err, "original schedules error")
+
+	for _, abCfg := range []prayer.AqrabAlBiladConfig{
+		{},
+		{PerDay: true, Step: 1},
+		{Reference: &prayer.Coordinate{Latitude: 45, Longitude: td.Longitude}},
+	} {
+		cfg.HighLatitudeAdapter = prayer.AqrabAlBilad(abCfg)
+		schedules, err := prayer.Calculate(cfg, 2023)
+		assertNil(t, err, "adapted schedules error")
+
+		for i, s := range schedules {
+			o := original[i]
+			msg := fmt.Sprintf("aqrab al-bilad %s with %+v", s.Date, abCfg)
+			assertEqual(t, false, s.Fajr.IsZero(), msg+" fajr is empty")
+			assertEqual(t, false, s.Sunrise.IsZero(), msg+" sunrise is empty")
+			assertEqual(t, false, s.Maghrib.IsZero(), msg+" maghrib is empty")
+			assertEqual(t, false, s.Isha.IsZero(), msg+" isha is empty")
+			assertEqual(t, true, s.Fajr.Before(s.Sunrise), msg+" fajr after sunrise")
+			assertEqual(t, true, s.Maghrib.Before(s.Isha), msg+" isha before maghrib")
+
+			// Normal days are left as they are
+			if !o.Fajr.IsZero() && !o.Sunrise.IsZero() && !o.Maghrib.IsZero() && !o.Isha.IsZero() {
+				assertEqual(t, o, s, msg+" normal day is changed")
+			}
+		}
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	td := datatest.Tromso
+	cfg := prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.AstronomicalTwilight(),
+		PreciseToSeconds:   true,
+	}
+
+	calculate := func(adapter prayer.HighLatitudeAdapter) []prayer.Schedule {
+		cfg.HighLatitudeAdapter = adapter
+		schedules, err := prayer.Calculate(cfg, 2023)
+		assertNil(t, err, "schedules error")
+		return schedules
+	}
+
+	original := calculate(nil)
+	mecca := calculate(prayer.AlwaysMecca())
+	nearestDay := calculate(prayer.NearestDay())
+	maxDepression := calculate(prayer.MaxDepression(0))
+
+	// Chain with fallback: sunrise only missing in polar days and nights
+	chained := calculate(prayer.Chain(
+		prayer.LocalRelativeEstimation(),
+		prayer.When(prayer.TimeMissing(prayer.Sunrise), prayer.AlwaysMecca())))
+	for i, s := range chained {
+		msg := fmt.Sprintf("chained schedule %s", s.Date)
+		if original[i].Sunrise.IsZero() {
+			assertEqual(t, mecca[i], s, msg+" doesn't follow mecca")
+		} else {
+			assertEqual(t, original[i], s, msg+" is changed")
+		}
+	}
+
+	// Conditional adapter
+	conditional := calculate(prayer.When(prayer.DayShorterThan(4*time.Hour), prayer.AlwaysMecca()))
+	for i, s := range conditional {
+		o := original[i]
+		msg := fmt.Sprintf("conditional schedule %s", s.Date)
+		if !o.Sunrise.IsZero() && !o.Maghrib.IsZero() && o.Maghrib.Sub(o.Sunrise) >= 4*time.Hour {
+			assertEqual(t, o, s, msg+" is changed")
+		} else {
+			assertEqual(t, mecca[i], s, msg+" doesn't follow mecca")
+		}
+	}
+
+	// Adapter for each prayer
+	perPrayer := calculate(prayer.PerPrayer(map[prayer.Prayer]prayer.HighLatitudeAdapter{
+		prayer.Fajr: prayer.NearestDay(),
+		prayer.Isha: prayer.MaxDepression(0),
+	}))
+	for i, s := range perPrayer {
+		msg := fmt.Sprintf("per prayer schedule %s", s.Date)
+		assertEqual(t, nearestDay[i].Fajr, s.Fajr, msg+" fajr doesn't follow nearest day")
+		assertEqual(t, maxDepression[i].Isha, s.Isha, msg+" isha doesn't follow max depression")
+		assertEqual(t, original[i].Sunrise, s.Sunrise, msg+" sunrise is changed")
+		assertEqual(t, original[i].Maghrib, s.Maghrib, msg+" maghrib is changed")
+	}
+}
+
+func TestVirtualNight(t *testing.T) {
+	td := datatest.Tromso
+	cfg := prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.AstronomicalTwilight(),
+		PreciseToSeconds:   true,
+	}
+
+	original, err := prayer.Calculate(cfg, 2023)
+	assertNil(t, err, "original schedules error")
+
+	for name, adapter := range map[string]prayer.HighLatitudeAdapter{
+		"angle based":       prayer.AngleBased(),
+		"one seventh night": prayer.OneSeventhNight(),
+		"middle night":      prayer.MiddleNight(),
+	} {
+		cfg.HighLatitudeAdapter = adapter
+		schedules, err := prayer.Calculate(cfg, 2023)
+		assertNil(t, err, name+" schedules error")
+
+		for i, s := range schedules {
+			o := original[i]
+			msg := fmt.Sprintf("%s %s", name, s.Date)
+			assertEqual(t, false, s.Fajr.IsZero(), msg+" fajr is empty")
+			assertEqual(t, false, s.Isha.IsZero(), msg+" isha is empty")
+			assertEqual(t, true, s.Fajr.Before(s.Zuhr), msg+" fajr after zuhr")
+			assertEqual(t, true, s.Isha.After(s.Zuhr), msg+" isha before zuhr")
+			assertEqual(t, o.Sunrise, s.Sunrise, msg+" sunrise is changed")
+			assertEqual(t, o.Maghrib, s.Maghrib, msg+" maghrib is changed")
+			if !o.Fajr.IsZero() && !o.Isha.IsZero() {
+				assertEqual(t, o, s, msg+" normal day is changed")
+			}
+		}
+	}
+}
+
+func TestMissingAsr(t *testing.T) {
+	// Around the North Pole the Sun never reaches the Asr shadow elevation in most
+	// of the year, even in polar day.
+	for _, asr := range []prayer.AsrConvention{prayer.Shafii, prayer.Hanafi} {
+		cfg := prayer.Config{
+			Latitude:            88,
+			Longitude:           18.9,
+			Timezone:            time.UTC,
+			AsrConvention:       asr,
+			HighLatitudeAdapter: prayer.MaxDepression(0),
+			PreciseToSeconds:    true,
+		}
+
+		// By default the missing Asr is left as it is
+		schedules, err := prayer.Calculate(cfg, 2023)
+		assertNil(t, err, "schedules error")
+		assertEqual(t, true, schedules[0].Asr.IsZero(), fmt.Sprintf("asr with %s is estimated without option", asr))
+
+		cfg.EstimateMissingAsr = true
+		schedules, err = prayer.Calculate(cfg, 2023)
+		assertNil(t, err, "schedules error")
+
+		for _, s := range schedules {
+			msg := fmt.Sprintf("asr %s with %s", s.Date, asr)
+			assertEqual(t, false, s.Asr.IsZero(), msg+" is empty")
+			assertEqual(t, false, s.Asr.Before(s.Zuhr), msg+" before zuhr")
+		}
+	}
+}
+
+func TestLocalRelativeEstimation(t *testing.T) {
+	td := datatest.London
+	cfg := prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.AstronomicalTwilight(),
+		PreciseToSeconds:   true,
+	}
+
+	calculate := func(adapter prayer.HighLatitudeAdapter) []prayer.Schedule {
+		cfg.HighLatitudeAdapter = adapter
+		schedules, err := prayer.Calculate(cfg, 2023)
+		assertNil(t, err, "schedules error")
+		return schedules
+	}
+
+	original := calculate(nil)
+	yearly := calculate(prayer.LocalRelativeEstimation())
+	for _, lreCfg := range []prayer.LocalRelativeEstimationConfig{
+		{NearestDays: 30},
+		{WeightByDistance: true},
+	} {
+		var nDifferent int
+		schedules := calculate(prayer.LocalRelativeEstimationWith(lreCfg))
+		for i, s := range schedules {
+			msg := fmt.Sprintf("local relative estimation %s with %+v", s.Date, lreCfg)
+			assertEqual(t, false, s.Fajr.IsZero(), msg+" fajr is empty")
+			assertEqual(t, false, s.Isha.IsZero(), msg+" isha is empty")
+			assertEqual(t, true, s.Fajr.Before(s.Sunrise), msg+" fajr after sunrise")
+			assertEqual(t, true, s.Isha.After(s.Maghrib), msg+" isha before maghrib")
+			if !original[i].Fajr.IsZero() && !original[i].Isha.IsZero() {
+				assertEqual(t, original[i], s, msg+" normal day is changed")
+			}
+			if s.Fajr != yearly[i].Fajr {
+				nDifferent++
+			}
+		}
+
+		msg := fmt.Sprintf("local relative estimation with %+v is the same as yearly", lreCfg)
+		assertLTE(t, 1, nDifferent, msg)
+	}
+
+	// When there are no samples, the times are left as they are
+	cfg.TwilightConvention = &prayer.TwilightConvention{FajrAngle: 80, IshaAngle: 17}
+	for _, s := range calculate(prayer.LocalRelativeEstimation()) {
+		assertEqual(t, true, s.Fajr.IsZero(), fmt.Sprintf("fajr %s without samples is not empty", s.Date))
+	}
+}
+
+func TestDiyanetHighLatitude(t *testing.T) {
+	conventions := map[string]*prayer.TwilightConvention{
+		"diyanet": prayer.Diyanet(),
+		"kemenag": prayer.Kemenag(), // 20° Fajr is not reached at 48° in summer
+	}
+
+	for _, td := range []datatest.TestData{datatest.London, datatest.Tromso} {
+		for name, tc := range conventions {
+			cfg := prayer.Config{
+				Latitude:           td.Latitude,
+				Longitude:          td.Longitude,
+				Timezone:           td.Timezone,
+				TwilightConvention: tc,
+				PreciseToSeconds:   true,
+			}
+
+			original, err := prayer.Calculate(cfg, 2023)
+			assertNil(t, err, "original schedules error")
+
+			cfg.HighLatitudeAdapter = prayer.DiyanetHighLatitude()
+			schedules, err := prayer.Calculate(cfg, 2023)
+			assertNil(t, err, "adapted schedules error")
+
+			for i, s := range schedules {
+				o := original[i]
+				msg := fmt.Sprintf("diyanet %s %s %s", td.Name, name, s.Date)
+				assertEqual(t, true, s.Fajr.Before(s.Zuhr) && s.Zuhr.Sub(s.Fajr) < 24*time.Hour, msg+" fajr is invalid")
+				assertEqual(t, true, s.Isha.After(s.Zuhr) && s.Isha.Sub(s.Zuhr) < 24*time.Hour, msg+" isha is invalid")
+				if !s.Sunrise.IsZero() {
+					assertEqual(t, true, s.Fajr.Before(s.Sunrise), msg+" fajr after sunrise")
+				}
+				if !s.Maghrib.IsZero() {
+					assertEqual(t, true, s.Isha.After(s.Maghrib), msg+" isha before maghrib")
+				}
+
+				// In London the estimated times change at most around 5 minutes per day
+				if td.Name != datatest.London.Name || i == 0 {
+					continue
+				}
+
+				prev := schedules[i-1]
+				if o.Fajr.IsZero() {
+					diff := s.Zuhr.Sub(s.Fajr) - prev.Zuhr.Sub(prev.Fajr)
+					assertLTE(t, diff.Abs(), 6*time.Minute, msg+" fajr jumps")
+				}
+				if o.Isha.IsZero() {
+					diff := s.Isha.Sub(s.Zuhr) - prev.Isha.Sub(prev.Zuhr)
+					assertLTE(t, diff.Abs(), 6*time.Minute, msg+" isha jumps")
+				}
+			}
+		}
+	}
+}
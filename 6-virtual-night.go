@@ -0,0 +1,123 @@
+package prayer
+
+import (
+	"time"
+)
+
+// virtualSunTimes returns the sunrise and Maghrib for each day. If they don't exist
+// in a day (e.g. in polar day or night), a virtual time is used instead, which is
+// interpolated linearly from the last and the first days that have them, relative
+// to the transit time. The interpolation continues across the year boundary. If
+// they never exist in the entire year, the times will be left as zero.
+func virtualSunTimes(schedules []Schedule) (sunrises, maghribs []time.Time) {
+	nSchedules := len(schedules)
+	riseTransits := make([]time.Duration, nSchedules)
+	transitSets := make([]time.Duration, nSchedules)
+	hasRise := make([]bool, nSchedules)
+	hasSet := make([]bool, nSchedules)
+	for i, s := range schedules {
+		if !s.Sunrise.IsZero() {
+			riseTransits[i] = s.Zuhr.Sub(s.Sunrise)
+			hasRise[i] = true
+		}
+
+		if !s.Maghrib.IsZero() {
+			transitSets[i] = s.Maghrib.Sub(s.Zuhr)
+			hasSet[i] = true
+		}
+	}
+
+	riseTransits = interpolateCircular(riseTransits, hasRise)
+	transitSets = interpolateCircular(transitSets, hasSet)
+
+	sunrises = make([]time.Time, nSchedules)
+	maghribs = make([]time.Time, nSchedules)
+	for i, s := range schedules {
+		sunrises[i], maghribs[i] = s.Sunrise, s.Maghrib
+		if sunrises[i].IsZero() && riseTransits[i] != 0 {
+			sunrises[i] = s.Zuhr.Add(-riseTransits[i])
+		}
+
+		if maghribs[i].IsZero() && transitSets[i] != 0 {
+			maghribs[i] = s.Zuhr.Add(transitSets[i])
+		}
+	}
+
+	return
+}
+
+// interpolateCircular fills the values that don't exist by interpolating the nearest
+// existing values before and after it, circularly.
+func interpolateCircular[T time.Duration | float64](values []T, exists []bool) []T {
+	n := len(values)
+	result := make([]T, n)
+	for i := range values {
+		if exists[i] {
+			result[i] = values[i]
+			continue
+		}
+
+		// Find the nearest existing values
+		prevDistance, nextDistance := 0, 0
+		for d := 1; d < n && prevDistance == 0; d++ {
+			if exists[sliceRealIdx(exists, i-d)] {
+				prevDistance = d
+			}
+		}
+
+		for d := 1; d < n && nextDistance == 0; d++ {
+			if exists[sliceRealIdx(exists, i+d)] {
+				nextDistance = d
+			}
+		}
+
+		if prevDistance == 0 || nextDistance == 0 {
+			continue
+		}
+
+		prev := sliceAt(values, i-prevDistance)
+		next := sliceAt(values, i+nextDistance)
+		ratio := float64(prevDistance) / float64(prevDistance+nextDistance)
+		result[i] = prev + T(float64(next-prev)*ratio)
+	}
+
+	return result
+}
+
+// fillMissingAsr estimates Asr in the days where the Sun never reaches the Asr shadow
+// elevation, e.g. in deep polar night or polar day. The Asr is put at the proportion
+// of the period between transit and Maghrib (virtual Maghrib if the Sun doesn't set),
+// where the proportion is interpolated from the nearest days that have Asr.
+func fillMissingAsr(schedules []Schedule) []Schedule {
+	_, maghribs := virtualSunTimes(schedules)
+
+	nSchedules := len(schedules)
+	nMissing := 0
+	ratios := make([]float64, nSchedules)
+	hasRatio := make([]bool, nSchedules)
+	for i, s := range schedules {
+		if s.Asr.IsZero() {
+			nMissing++
+			continue
+		}
+
+		if !s.Maghrib.IsZero() && s.Maghrib.After(s.Zuhr) {
+			ratios[i] = float64(s.Asr.Sub(s.Zuhr)) / float64(s.Maghrib.Sub(s.Zuhr))
+			hasRatio[i] = true
+		}
+	}
+
+	if nMissing == 0 {
+		return schedules
+	}
+
+	ratios = interpolateCircular(ratios, hasRatio)
+	for i, s := range schedules {
+		if s.Asr.IsZero() && ratios[i] != 0 && !maghribs[i].IsZero() {
+			asrDuration := float64(maghribs[i].Sub(s.Zuhr)) * ratios[i]
+			schedules[i].Asr = s.Zuhr.Add(time.Duration(asrDuration))
+		}
+	}
+
+	return schedules
+}
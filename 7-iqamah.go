@@ -0,0 +1,185 @@
+package prayer
+
+import (
+	"time"
+)
+
+// IqamahRule is the rule for deriving the iqamah (congregation) time from the adhan
+// time of a prayer. The rule is applied in following order: offset, fixed time,
+// minimum gap and finally rounding.
+type IqamahRule struct {
+	// Offset is the fixed duration between adhan and iqamah.
+	Offset time.Duration
+
+	// FixedTime is the fixed clock time for the iqamah, specified as duration since
+	// midnight, e.g. 20 hours for "Isha at 20:00". If adhan plus offset occurs later
+	// than this clock time, the later time will be used instead. If zero, it will
+	// not be used.
+	FixedTime time.Duration
+
+	// MinGap is the minimum duration between adhan and iqamah.
+	MinGap time.Duration
+
+	// RoundTo rounds up the iqamah time to the next multiple of this duration, e.g.
+	// 5 or 15 minutes. If zero, the time will not be rounded.
+	RoundTo time.Duration
+
+	// Weekly specify whether the iqamah should be stable for the entire week. If
+	// enabled, the iqamah is derived from the latest adhan time in the week, where
+	// adhan after midnight (e.g. Isha in summer) is later than the one before it.
+	Weekly bool
+}
+
+// IqamahRules is the set of iqamah rules for each prayer.
+type IqamahRules struct {
+	Fajr    IqamahRule
+	Zuhr    IqamahRule
+	Asr     IqamahRule
+	Maghrib IqamahRule
+	Isha    IqamahRule
+
+	// Jumuah is the rules for each Jumu'ah slot on Friday, derived from the Zuhr
+	// adhan time.
+	Jumuah []IqamahRule
+
+	// WeekStart is the first day of the week, used by rules with weekly stable time.
+	// By default it will use Sunday.
+	WeekStart time.Weekday
+}
+
+// IqamahSchedule is the iqamah time of each prayer on a day.
+type IqamahSchedule struct {
+	// Date is the ISO date, useful for logging.
+	Date string
+
+	Fajr    time.Time
+	Zuhr    time.Time
+	Asr     time.Time
+	Maghrib time.Time
+	Isha    time.Time
+
+	// Jumuah is the time of each Jumu'ah slot. Only exist on Friday.
+	Jumuah []time.Time
+}
+
+// CalculateIqamah derives the iqamah schedules from the adhan schedules using the
+// specified rules.
+func CalculateIqamah(schedules []Schedule, rules IqamahRules) []IqamahSchedule {
+	// Group the schedules by week, used for weekly stable rules
+	weeks := make(map[string][]int)
+	weekKeys := make([]string, len(schedules))
+	for i, s := range schedules {
+		shift := (int(s.Zuhr.Weekday()) - int(rules.WeekStart) + 7) % 7
+		key := s.Zuhr.AddDate(0, 0, -shift).Format("2006-01-02")
+		weeks[key] = append(weeks[key], i)
+		weekKeys[i] = key
+	}
+
+	// Create function to fetch the latest adhan in the week. The times are compared
+	// by their wall clock since the midnight of their day, so Isha that occurs after
+	// midnight is still counted as the latest.
+	latestInWeek := func(idx int, p Prayer) time.Time {
+		var latestClock time.Duration
+		for _, i := range weeks[weekKeys[idx]] {
+			s := schedules[i]
+			if t := s.Time(p); !t.IsZero() && dayClockTime(s.Zuhr, t) > latestClock {
+				latestClock = dayClockTime(s.Zuhr, t)
+			}
+		}
+		return atClockTime(schedules[idx].Zuhr, latestClock)
+	}
+
+	// Apply the rules
+	iqamahSchedules := make([]IqamahSchedule, len(schedules))
+	for i, s := range schedules {
+		apply := func(p Prayer, rule IqamahRule) time.Time {
+			adhan := s.Time(p)
+			if adhan.IsZero() {
+				return time.Time{}
+			}
+
+			base := adhan
+			if rule.Weekly {
+				base = latestInWeek(i, p)
+			}
+			return applyIqamahRule(s.Zuhr, adhan, base, rule)
+		}
+
+		is := IqamahSchedule{
+			Date:    s.Date,
+			Fajr:    apply(Fajr, rules.Fajr),
+			Zuhr:    apply(Zuhr, rules.Zuhr),
+			Asr:     apply(Asr, rules.Asr),
+			Maghrib: apply(Maghrib, rules.Maghrib),
+			Isha:    apply(Isha, rules.Isha),
+		}
+
+		if s.Zuhr.Weekday() == time.Friday {
+			for _, rule := range rules.Jumuah {
+				is.Jumuah = append(is.Jumuah, apply(Zuhr, rule))
+			}
+		}
+
+		iqamahSchedules[i] = is
+	}
+
+	return iqamahSchedules
+}
+
+// applyIqamahRule applies the rule for the adhan in the specified day. The day is
+// used for the fixed time, since the adhan might occur after midnight.
+func applyIqamahRule(day, adhan, base time.Time, rule IqamahRule) time.Time {
+	// Apply offset
+	t := base.Add(rule.Offset)
+
+	// Apply fixed time, unless the adhan is later
+	if rule.FixedTime > 0 {
+		if fixed := atClockTime(day, rule.FixedTime); fixed.After(t) {
+			t = fixed
+		}
+	}
+
+	// Apply minimum gap
+	if minTime := adhan.Add(rule.MinGap); t.Before(minTime) {
+		t = minTime
+	}
+
+	// Round up the time
+	if rule.RoundTo > 0 {
+		ct := clockTime(t)
+		if remainder := ct % rule.RoundTo; remainder != 0 {
+			t = atClockTime(t, ct-remainder+rule.RoundTo)
+		}
+	}
+
+	return t
+}
+
+// clockTime returns the wall clock of t as duration since midnight.
+func clockTime(t time.Time) time.Duration {
+	h, m, s := t.Clock()
+	return time.Duration(h)*time.Hour +
+		time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second +
+		time.Duration(t.Nanosecond())
+}
+
+// dayClockTime returns the wall clock of t as duration since the midnight of day. If t
+// occurs in the next day, the duration will be more than 24 hours, while if it occurs
+// in the previous day the duration will be negative.
+func dayClockTime(day, t time.Time) time.Duration {
+	t = t.In(day.Location())
+	nDays := julianDayNumber(t) - julianDayNumber(day)
+	return time.Duration(nDays)*24*time.Hour + clockTime(t)
+}
+
+// atClockTime returns the time at the specified wall clock in the same date as t. If
+// the clock is more than 24 hours, it will be in the next day. The clock is split into
+// its components since the nanoseconds might overflow int in 32-bit platforms.
+func atClockTime(t time.Time, clock time.Duration) time.Time {
+	h := int(clock / time.Hour)
+	m := int(clock % time.Hour / time.Minute)
+	sec := int(clock % time.Minute / time.Second)
+	nsec := int(clock % time.Second)
+	return time.Date(t.Year(), t.Month(), t.Day(), h, m, sec, nsec, t.Location())
+}
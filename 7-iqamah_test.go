@@ -0,0 +1,109 @@
+package prayer_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+)
+
+func TestCalculateIqamah(t *testing.T) {
+	// Prepare a week of adhan schedules, from Monday to Sunday
+	tz := time.UTC
+	var schedules []prayer.Schedule
+	for i := 0; i < 7; i++ {
+		date := time.Date(2023, 1, 2+i, 0, 0, 0, 0, tz)
+		schedules = append(schedules, prayer.Schedule{
+			Date:    date.Format("2006-01-02"),
+			Fajr:    date.Add(4*time.Hour + time.Duration(i)*time.Minute),
+			Zuhr:    date.Add(12*time.Hour + 3*time.Minute),
+			Asr:     date.Add(15*time.Hour + 21*time.Minute),
+			Maghrib: date.Add(18*time.Hour + 11*time.Minute),
+			Isha:    date.Add(19*time.Hour + 50*time.Minute + time.Duration(i)*5*time.Minute),
+		})
+	}
+
+	iqamahSchedules := prayer.CalculateIqamah(schedules, prayer.IqamahRules{
+		Fajr:    prayer.IqamahRule{Offset: 20 * time.Minute, Weekly: true},
+		Zuhr:    prayer.IqamahRule{Offset: 10 * time.Minute, RoundTo: 15 * time.Minute},
+		Asr:     prayer.IqamahRule{Offset: 10 * time.Minute, RoundTo: 5 * time.Minute},
+		Maghrib: prayer.IqamahRule{MinGap: 7 * time.Minute},
+		Isha:    prayer.IqamahRule{FixedTime: 20 * time.Hour, MinGap: 5 * time.Minute},
+		Jumuah: []prayer.IqamahRule{
+			{FixedTime: 13 * time.Hour},
+			{FixedTime: 14 * time.Hour},
+		},
+		WeekStart: time.Monday,
+	})
+
+	clock := func(t time.Time) string { return t.Format("15:04") }
+	for i, is := range iqamahSchedules {
+		// Fajr is stable for the week, using the latest adhan
+		assertIqamah(t, is.Date, "Fajr", "04:26", clock(is.Fajr))
+		assertIqamah(t, is.Date, "Zuhr", "12:15", clock(is.Zuhr))
+		assertIqamah(t, is.Date, "Asr", "15:35", clock(is.Asr))
+		assertIqamah(t, is.Date, "Maghrib", "18:18", clock(is.Maghrib))
+
+		// Isha is fixed at 20:00, unless adhan plus minimum gap is later
+		expectedIsha := "20:00"
+		if i > 1 {
+			expectedIsha = clock(schedules[i].Isha.Add(5 * time.Minute))
+		}
+		assertIqamah(t, is.Date, "Isha", expectedIsha, clock(is.Isha))
+
+		// Jumu'ah only on Friday
+		expectedJumuah := 0
+		if i == 4 {
+			expectedJumuah = 2
+		}
+		assertIqamah(t, is.Date, "Jumuah slots", fmt.Sprint(expectedJumuah), fmt.Sprint(len(is.Jumuah)))
+	}
+}
+
+func TestCalculateIqamahAfterMidnight(t *testing.T) {
+	// Prepare a week of adhan schedules in summer of high latitude, where Isha
+	// occurs after midnight since Wednesday
+	tz := time.UTC
+	var schedules []prayer.Schedule
+	for i := 0; i < 7; i++ {
+		date := time.Date(2023, 6, 5+i, 0, 0, 0, 0, tz)
+		schedules = append(schedules, prayer.Schedule{
+			Date:    date.Format("2006-01-02"),
+			Zuhr:    date.Add(13*time.Hour + 10*time.Minute),
+			Maghrib: date.Add(22*time.Hour + 10*time.Minute),
+			Isha:    date.Add(23*time.Hour + 50*time.Minute + time.Duration(i)*5*time.Minute),
+		})
+	}
+
+	// Isha is stable using the latest adhan at 00:20 in the next day
+	iqamahSchedules := prayer.CalculateIqamah(schedules, prayer.IqamahRules{
+		Isha:      prayer.IqamahRule{Offset: 10 * time.Minute, Weekly: true},
+		WeekStart: time.Monday,
+	})
+
+	for i, is := range iqamahSchedules {
+		nextDay := time.Date(2023, 6, 6+i, 0, 0, 0, 0, tz)
+		expected := nextDay.Add(30 * time.Minute).Format(time.RFC3339)
+		assertIqamah(t, is.Date, "weekly Isha", expected, is.Isha.Format(time.RFC3339))
+	}
+
+	// Fixed time is in the same day as the schedule, so adhan after midnight is
+	// always later than it
+	iqamahSchedules = prayer.CalculateIqamah(schedules, prayer.IqamahRules{
+		Isha: prayer.IqamahRule{FixedTime: 23*time.Hour + 55*time.Minute},
+	})
+
+	for i, is := range iqamahSchedules {
+		expected := time.Date(2023, 6, 5+i, 23, 55, 0, 0, tz)
+		if adhan := schedules[i].Isha; adhan.After(expected) {
+			expected = adhan
+		}
+		assertIqamah(t, is.Date, "fixed Isha", expected.Format(time.RFC3339), is.Isha.Format(time.RFC3339))
+	}
+}
+
+func assertIqamah(t *testing.T, date, name, expected, result string) {
+	msg := fmt.Sprintf("iqamah %s in %s: want %s got %s", name, date, expected, result)
+	assertEqual(t, expected, result, msg)
+}
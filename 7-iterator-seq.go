@@ -0,0 +1,18 @@
+//go:build go1.23
+
+package prayer
+
+import "iter"
+
+// All returns the schedules from the iterator as a sequence, to be used within
+// range-over-func loop. The sequence stops after the first error.
+func (it *Iterator) All() iter.Seq2[Schedule, error] {
+	return func(yield func(Schedule, error) bool) {
+		for {
+			s, err := it.Next()
+			if !yield(s, err) || err != nil {
+				return
+			}
+		}
+	}
+}
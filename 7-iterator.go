@@ -0,0 +1,160 @@
+package prayer
+
+import (
+	"time"
+)
+
+// iteratorTransitionDays is the number of days at the end of each year that blended
+// with the schedules of the next year.
+const iteratorTransitionDays = 30
+
+// Iterator produces the prayer schedule day by day, starting from a specified date
+// and continuing indefinitely across year boundaries.
+type Iterator struct {
+	cfg        Config
+	err        error
+	finalizer  *scheduleFinalizer
+	idx        int
+	current    *iteratorYear
+	next       *iteratorYear
+	transition ScheduleCorrections
+}
+
+// iteratorYear is the schedules of a year, before and after adjusted by the high
+// latitude adapter.
+type iteratorYear struct {
+	year     int
+	raw      []Schedule
+	adjusted []Schedule
+}
+
+// Iterate returns an iterator that produces the prayer schedule for each day, starting
+// from the date of `from` in the configured timezone.
+//
+// The schedules are calculated lazily one year at a time, and the next year is
+// prefetched near the end of current year, so at most two years of schedules are
+// kept in memory. Since each year is still calculated as a whole, the high latitude
+// adapters that need to look at the surrounding days work as usual. However, each
+// year is adjusted separately, so the adjusted times might jump between December 31
+// and January 1. To prevent it, the jump is gradually spread over the last 30 days
+// of the year, which means those days might differ slightly from `Calculate`.
+func Iterate(cfg Config, from time.Time) *Iterator {
+	// Apply the default config once, so the time zone is not looked up for every
+	// year. If it can't be resolved, the error will be returned by `Next`.
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return &Iterator{err: err}
+	}
+
+	from = from.In(cfg.Timezone)
+	return &Iterator{
+		cfg:       cfg,
+		finalizer: newScheduleFinalizer(cfg),
+		idx:       from.YearDay() - 1,
+		current:   &iteratorYear{year: from.Year()},
+	}
+}
+
+// Next returns the schedule for the next day. If the calculation for a day failed,
+// the error will be returned and the iterator will retry the same day on the next
+// call.
+func (it *Iterator) Next() (Schedule, error) {
+	if it.err != nil {
+		return Schedule{}, it.err
+	}
+
+	// Calculate the schedules for current year if needed
+	current := it.current
+	if current.adjusted == nil {
+		current.raw, current.adjusted = calcAdjusted(it.cfg, current.year)
+	}
+
+	// Near the end of year, prefetch the next year to find the jump in the adjusted
+	// times between both years
+	nDays := len(current.adjusted)
+	remaining := nDays - it.idx
+	if it.cfg.HighLatitudeAdapter != nil && remaining <= iteratorTransitionDays && it.next == nil {
+		next := &iteratorYear{year: current.year + 1}
+		next.raw, next.adjusted = calcAdjusted(it.cfg, next.year)
+		it.next = next
+		it.transition = yearTransition(current, next)
+	}
+
+	// Fetch the schedule and spread the jump gradually until the end of year
+	s := current.adjusted[it.idx]
+	if it.next != nil {
+		weight := float64(iteratorTransitionDays-remaining+1) / iteratorTransitionDays
+		s.Fajr = applyCorrection(s.Fajr, scaleDuration(it.transition.Fajr, weight))
+		s.Sunrise = applyCorrection(s.Sunrise, scaleDuration(it.transition.Sunrise, weight))
+		s.Asr = applyCorrection(s.Asr, scaleDuration(it.transition.Asr, weight))
+		s.Maghrib = applyCorrection(s.Maghrib, scaleDuration(it.transition.Maghrib, weight))
+		s.Isha = applyCorrection(s.Isha, scaleDuration(it.transition.Isha, weight))
+	}
+
+	date := time.Date(current.year, 1, it.idx+1, 0, 0, 0, 0, it.cfg.Timezone)
+	s, err := it.finalizer.finalize(s, date)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	// If all schedules in this year has been consumed, move to the next year
+	it.idx++
+	if it.idx >= nDays {
+		it.idx = 0
+		it.current = it.next
+		it.next = nil
+		it.transition = ScheduleCorrections{}
+		if it.current == nil {
+			it.current = &iteratorYear{year: current.year + 1}
+		}
+	}
+
+	return s, nil
+}
+
+// yearTransition returns the jump of the adjusted times between the last day of
+// current year and the first day of the next year. To exclude the natural daily
+// change, the jump is measured on the distance of each time from Zuhr, then reduced
+// by the average change in the days around it. The times that not adjusted in both
+// days have no jump.
+func yearTransition(current, next *iteratorYear) ScheduleCorrections {
+	nDays := len(current.adjusted)
+	if nDays < 2 || len(next.adjusted) < 2 {
+		return ScheduleCorrections{}
+	}
+
+	c1, c0 := current.adjusted[nDays-2], current.adjusted[nDays-1]
+	n0, n1 := next.adjusted[0], next.adjusted[1]
+	rawC0, rawN0 := current.raw[nDays-1], next.raw[0]
+
+	jump := func(get func(Schedule) time.Time) time.Duration {
+		// If the time is not adjusted in both sides, there is no jump
+		if get(c0).Equal(get(rawC0)) && get(n0).Equal(get(rawN0)) {
+			return 0
+		}
+
+		// The jump can only be measured if the time exists in all days
+		for _, s := range []Schedule{c1, c0, n0, n1} {
+			if get(s).IsZero() {
+				return 0
+			}
+		}
+
+		offset := func(s Schedule) time.Duration { return get(s).Sub(s.Zuhr) }
+		change := offset(n0) - offset(c0)
+		avgChange := (offset(c0) - offset(c1) + offset(n1) - offset(n0)) / 2
+		return change - avgChange
+	}
+
+	return ScheduleCorrections{
+		Fajr:    jump(func(s Schedule) time.Time { return s.Fajr }),
+		Sunrise: jump(func(s Schedule) time.Time { return s.Sunrise }),
+		Asr:     jump(func(s Schedule) time.Time { return s.Asr }),
+		Maghrib: jump(func(s Schedule) time.Time { return s.Maghrib }),
+		Isha:    jump(func(s Schedule) time.Time { return s.Isha }),
+	}
+}
+
+func scaleDuration(d time.Duration, factor float64) time.Duration {
+	return time.Duration(float64(d) * factor)
+}
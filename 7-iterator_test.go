@@ -0,0 +1,69 @@
+package prayer_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+	"github.com/hablullah/go-prayer/internal/datatest"
+)
+
+func TestIterate(t *testing.T) {
+	td := datatest.London
+	cfg := prayer.Config{
+		Latitude:            td.Latitude,
+		Longitude:           td.Longitude,
+		Timezone:            td.Timezone,
+		TwilightConvention:  prayer.AstronomicalTwilight(),
+		HighLatitudeAdapter: prayer.NearestLatitude(),
+		PreciseToSeconds:    true,
+	}
+
+	// Prepare the expected schedules in both years
+	schedules2023, err := prayer.Calculate(cfg, 2023)
+	assertNil(t, err, fmt.Sprintf("calculate 2023 has error: %v", err))
+	schedules2024, err := prayer.Calculate(cfg, 2024)
+	assertNil(t, err, fmt.Sprintf("calculate 2024 has error: %v", err))
+	expected := append(schedules2023[len(schedules2023)-3:], schedules2024[:3]...)
+
+	// Iterate across year boundary
+	from := time.Date(2023, 12, 29, 0, 0, 0, 0, td.Timezone)
+	it := prayer.Iterate(cfg, from)
+	for _, e := range expected {
+		r, err := it.Next()
+		assertNil(t, err, fmt.Sprintf("iterate %s has error: %v", e.Date, err))
+		assertEqual(t, e.Date, r.Date, fmt.Sprintf("iterate date: want %s got %s", e.Date, r.Date))
+		assertSchedule(t, td, e, r)
+	}
+}
+
+func TestIterateYearTransition(t *testing.T) {
+	// In Ushuaia the abnormal days happen in summer, around the new year
+	tz := time.FixedZone("-03", -3*60*60)
+	cfg := prayer.Config{
+		Latitude:            -54.8,
+		Longitude:           -68.3,
+		Timezone:            tz,
+		TwilightConvention:  prayer.AstronomicalTwilight(),
+		HighLatitudeAdapter: prayer.LocalRelativeEstimation(),
+		PreciseToSeconds:    true,
+	}
+
+	// The daily change in the new year must be similar with the days before it
+	it := prayer.Iterate(cfg, time.Date(2023, 12, 29, 0, 0, 0, 0, tz))
+	var schedules []prayer.Schedule
+	for i := 0; i < 5; i++ {
+		s, err := it.Next()
+		assertNil(t, err, fmt.Sprintf("iterate has error: %v", err))
+		schedules = append(schedules, s)
+	}
+
+	for i := 2; i < len(schedules); i++ {
+		s, prev, prev2 := schedules[i], schedules[i-1], schedules[i-2]
+		fajrChange := s.Fajr.Sub(prev.Fajr) - prev.Fajr.Sub(prev2.Fajr)
+		ishaChange := s.Isha.Sub(prev.Isha) - prev.Isha.Sub(prev2.Isha)
+		assertLTE(t, fajrChange.Abs(), 5*time.Second, fmt.Sprintf("fajr in %s jumps %v", s.Date, fajrChange))
+		assertLTE(t, ishaChange.Abs(), 5*time.Second, fmt.Sprintf("isha in %s jumps %v", s.Date, ishaChange))
+	}
+}
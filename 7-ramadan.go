@@ -0,0 +1,134 @@
+package prayer
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultImsakOffset is the common duration of Imsak before Fajr, which can be used as
+// `ImsakOffset` in `RamadanConfig`.
+const DefaultImsakOffset = 10 * time.Minute
+
+// RamadanConfig is configuration for creating Ramadan timetable.
+type RamadanConfig struct {
+	// ImsakOffset is the duration of Imsak before Fajr, i.e. the time when people
+	// are reminded to stop eating the pre-dawn meal (suhoor). If zero, Imsak is at
+	// Fajr time. Most timetables use `DefaultImsakOffset`.
+	ImsakOffset time.Duration
+
+	// TarawihOffset is the duration of Tarawih after Isha. If zero, Tarawih starts
+	// at Isha time.
+	TarawihOffset time.Duration
+}
+
+// RamadanDay is the schedule for a day in Ramadan.
+type RamadanDay struct {
+	// Schedule is the prayer schedule of the day. The Maghrib is the time to break
+	// the fast (iftar).
+	Schedule
+
+	// Imsak is the time to stop eating the pre-dawn meal, a while before Fajr.
+	Imsak time.Time
+
+	// Tarawih is the time of the Tarawih prayer, after Isha.
+	Tarawih time.Time
+
+	// FastingDuration is the duration between Fajr and Maghrib. It will be zero if
+	// either Fajr or Maghrib doesn't exist, even after adjusted by the high latitude
+	// adapter.
+	FastingDuration time.Duration
+}
+
+// RamadanTimetable is the timetable for the entire month of Ramadan.
+type RamadanTimetable struct {
+	// HijriYear is the Hijri year of the Ramadan.
+	HijriYear int
+
+	// Days is the schedule for each day in Ramadan, ordered from the first day.
+	Days []RamadanDay
+
+	// Longest is the day with the longest fasting duration.
+	Longest RamadanDay
+
+	// Shortest is the day with the shortest fasting duration.
+	Shortest RamadanDay
+
+	// AverageFasting is the average of fasting duration. The days without fasting
+	// duration are excluded.
+	AverageFasting time.Duration
+}
+
+// CalculateRamadan calculates the timetable for the month of Ramadan in the specified
+// Hijri year. The days are decided using the Hijri calendar and offset in the config,
+// so the timetable might span across two Gregorian years. Since the schedules are
+// calculated using `Calculate`, the fasting durations are measured after adjusted by
+// the high latitude adapter and corrections.
+func CalculateRamadan(cfg Config, hijriYear int, rcfg RamadanConfig) (RamadanTimetable, error) {
+	// Find the Gregorian years that might contain the Ramadan. The tabular date is
+	// used as estimation, with margin for the calendar and the offset.
+	ramadanStart := jdnToTime(tabularHijriToJDN(hijriYear, 9, 1))
+	firstYear := ramadanStart.AddDate(0, 0, -15-cfg.HijriOffset).Year()
+	lastYear := ramadanStart.AddDate(0, 0, 45-cfg.HijriOffset).Year()
+
+	// The days are picked using their Hijri date, so make sure it's calculated
+	cfg.IncludeHijri = true
+
+	timetable := RamadanTimetable{HijriYear: hijriYear}
+	for year := firstYear; year <= lastYear; year++ {
+		schedules, err := Calculate(cfg, year)
+		if err != nil {
+			return RamadanTimetable{}, err
+		}
+
+		for _, s := range schedules {
+			if s.Hijri.Year != hijriYear || s.Hijri.Month != 9 {
+				continue
+			}
+
+			day := RamadanDay{Schedule: s}
+			if !s.Fajr.IsZero() {
+				day.Imsak = s.Fajr.Add(-rcfg.ImsakOffset)
+			}
+
+			if !s.Isha.IsZero() {
+				day.Tarawih = s.Isha.Add(rcfg.TarawihOffset)
+			}
+
+			if !s.Fajr.IsZero() && !s.Maghrib.IsZero() {
+				day.FastingDuration = s.Maghrib.Sub(s.Fajr)
+			}
+
+			timetable.Days = append(timetable.Days, day)
+		}
+	}
+
+	if len(timetable.Days) == 0 {
+		return RamadanTimetable{}, fmt.Errorf("ramadan %d is not found", hijriYear)
+	}
+
+	// Calculate the summary
+	var nFasting int
+	var totalFasting time.Duration
+	for _, day := range timetable.Days {
+		if day.FastingDuration <= 0 {
+			continue
+		}
+
+		if nFasting == 0 || day.FastingDuration > timetable.Longest.FastingDuration {
+			timetable.Longest = day
+		}
+
+		if nFasting == 0 || day.FastingDuration < timetable.Shortest.FastingDuration {
+			timetable.Shortest = day
+		}
+
+		nFasting++
+		totalFasting += day.FastingDuration
+	}
+
+	if nFasting > 0 {
+		timetable.AverageFasting = totalFasting / time.Duration(nFasting)
+	}
+
+	return timetable, nil
+}
@@ -0,0 +1,64 @@
+package prayer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+	"github.com/hablullah/go-prayer/internal/datatest"
+)
+
+func TestCalculateRamadan(t *testing.T) {
+	td := datatest.London
+	cfg := prayer.Config{
+		Latitude:            td.Latitude,
+		Longitude:           td.Longitude,
+		Timezone:            td.Timezone,
+		TwilightConvention:  prayer.MWL(),
+		HighLatitudeAdapter: prayer.NearestLatitude(),
+		HijriCalendar:       prayer.HijriUmmAlQura,
+	}
+
+	timetable, err := prayer.CalculateRamadan(cfg, 1444, prayer.RamadanConfig{
+		ImsakOffset: prayer.DefaultImsakOffset,
+	})
+	assertNil(t, err, "ramadan 1444 error")
+	assertEqual(t, 29, len(timetable.Days), "ramadan 1444 length")
+	assertEqual(t, "2023-03-23", timetable.Days[0].Date, "ramadan 1444 start")
+	assertEqual(t, "2023-04-20", timetable.Days[28].Date, "ramadan 1444 end")
+
+	for _, day := range timetable.Days {
+		assertEqual(t, 10*time.Minute, day.Fajr.Sub(day.Imsak), day.Date+" imsak")
+		assertEqual(t, day.Isha, day.Tarawih, day.Date+" tarawih")
+		assertEqual(t, day.Maghrib.Sub(day.Fajr), day.FastingDuration, day.Date+" fasting duration")
+	}
+
+	// In spring, the fasting is getting longer in the north
+	assertEqual(t, timetable.Days[28].Date, timetable.Longest.Date, "longest fast")
+	assertEqual(t, timetable.Days[0].Date, timetable.Shortest.Date, "shortest fast")
+	assertLTE(t, timetable.Shortest.FastingDuration, timetable.AverageFasting, "average fast too short")
+	assertLTE(t, timetable.AverageFasting, timetable.Longest.FastingDuration, "average fast too long")
+
+	// Ramadan 1420 spans across the Gregorian year boundary
+	cfg.HijriCalendar = prayer.HijriTabular
+	timetable, err = prayer.CalculateRamadan(cfg, 1420, prayer.RamadanConfig{
+		ImsakOffset:   15 * time.Minute,
+		TarawihOffset: 20 * time.Minute,
+	})
+	assertNil(t, err, "ramadan 1420 error")
+	assertEqual(t, 30, len(timetable.Days), "ramadan 1420 length")
+	assertEqual(t, "1999-12-09", timetable.Days[0].Date, "ramadan 1420 start")
+	assertEqual(t, "2000-01-07", timetable.Days[29].Date, "ramadan 1420 end")
+
+	first := timetable.Days[0]
+	assertEqual(t, 15*time.Minute, first.Fajr.Sub(first.Imsak), "ramadan 1420 imsak")
+	assertEqual(t, 20*time.Minute, first.Tarawih.Sub(first.Isha), "ramadan 1420 tarawih")
+
+	// Zero offsets put Imsak at Fajr and Tarawih at Isha
+	timetable, err = prayer.CalculateRamadan(cfg, 1420, prayer.RamadanConfig{})
+	assertNil(t, err, "ramadan 1420 without offset error")
+
+	first = timetable.Days[0]
+	assertEqual(t, first.Fajr, first.Imsak, "ramadan 1420 imsak without offset")
+	assertEqual(t, first.Isha, first.Tarawih, "ramadan 1420 tarawih without offset")
+}
@@ -0,0 +1,191 @@
+package prayer
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Clock is the source of current time that used by `Scheduler`. It's useful to
+// replace the system clock in tests, so the time can be fast-forwarded without
+// sleeping.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current time on
+	// the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock returns clock that uses the system time.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Alert is additional event that fired at an offset relative to a prayer time, e.g.
+// "15 min before Fajr" or "Iqamah 10 min after Zuhr".
+type Alert struct {
+	// Name is the name of the alert, e.g. "Iqamah".
+	Name string
+
+	// Prayer is the prayer time used as the base of the alert.
+	Prayer Prayer
+
+	// Offset is the duration relative to the prayer time. Use negative value to fire
+	// the alert before the prayer time.
+	Offset time.Duration
+}
+
+// Event is the notification that emitted by `Scheduler`.
+type Event struct {
+	// Prayer is the prayer time that triggered this event.
+	Prayer Prayer
+
+	// Alert is the name of alert that triggered this event. It will be empty if
+	// the event is for the prayer time itself.
+	Alert string
+
+	// Offset is the offset of the alert relative to the prayer time.
+	Offset time.Duration
+
+	// Time is the moment when the event is fired.
+	Time time.Time
+
+	// Schedule is the schedule of the day where the prayer time belongs.
+	Schedule Schedule
+}
+
+// Scheduler emits event at each prayer time and at each of the configured alerts.
+// The schedules are calculated using `Iterate`, so it keeps working across day and
+// year boundaries.
+type Scheduler struct {
+	cfg    Config
+	clock  Clock
+	alerts []Alert
+}
+
+// NewScheduler returns a new scheduler for the specified configuration. If clock is
+// nil, it will use the system clock.
+func NewScheduler(cfg Config, clock Clock, alerts ...Alert) *Scheduler {
+	if clock == nil {
+		clock = SystemClock()
+	}
+
+	return &Scheduler{
+		cfg:    cfg,
+		clock:  clock,
+		alerts: alerts,
+	}
+}
+
+// Run sends the events into the channel as they occur, until the context is
+// cancelled or the schedule calculation failed. Only events that occur after
+// Run is called will be sent. When Run returns, the channel will be closed, so
+// the events can be received using range loop.
+func (sc *Scheduler) Run(ctx context.Context, events chan<- Event) error {
+	defer close(events)
+
+	// Find the maximum alert offset, which used to make sure enough days
+	// already loaded before emitting an event.
+	var maxOffset time.Duration
+	for _, a := range sc.alerts {
+		if offset := a.Offset.Abs(); offset > maxOffset {
+			maxOffset = offset
+		}
+	}
+
+	// Start from yesterday, since in higher latitude yesterday's Isha
+	// might occur today.
+	now := sc.clock.Now()
+	it := Iterate(sc.cfg, now.AddDate(0, 0, -1))
+
+	var queue []Event
+	var loadedUntil time.Time
+	for {
+		// Make sure the schedules are loaded far enough after the next event
+		for len(queue) == 0 || !loadedUntil.After(queue[0].Time.Add(maxOffset+24*time.Hour)) {
+			s, err := it.Next()
+			if err != nil {
+				return err
+			}
+
+			for _, e := range sc.dailyEvents(s, now) {
+				queue = insertEvent(queue, e)
+			}
+			loadedUntil = s.Zuhr
+		}
+
+		// Wait until the next event
+		next := queue[0]
+		if wait := next.Time.Sub(sc.clock.Now()); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-sc.clock.After(wait):
+			}
+			continue
+		}
+
+		// Emit the event
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case events <- next:
+			queue = queue[1:]
+		}
+	}
+}
+
+func (sc *Scheduler) dailyEvents(s Schedule, start time.Time) []Event {
+	var events []Event
+	add := func(p Prayer, alert string, offset time.Duration) {
+		pt := s.Time(p)
+		if pt.IsZero() {
+			return
+		}
+
+		et := pt.Add(offset)
+		if !et.Before(start) {
+			events = append(events, Event{
+				Prayer:   p,
+				Alert:    alert,
+				Offset:   offset,
+				Time:     et,
+				Schedule: s,
+			})
+		}
+	}
+
+	for _, p := range []Prayer{Fajr, Sunrise, Zuhr, Asr, Maghrib, Isha} {
+		add(p, "", 0)
+	}
+
+	for _, a := range sc.alerts {
+		add(a.Prayer, a.Name, a.Offset)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	return events
+}
+
+// insertEvent inserts the event into the sorted queue. If there are events with the
+// same time, the new event will be put after them.
+func insertEvent(queue []Event, e Event) []Event {
+	idx := sort.Search(len(queue), func(i int) bool {
+		return queue[i].Time.After(e.Time)
+	})
+
+	queue = append(queue, Event{})
+	copy(queue[idx+1:], queue[idx:])
+	queue[idx] = e
+	return queue
+}
@@ -0,0 +1,92 @@
+package prayer_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+	"github.com/hablullah/go-prayer/internal/datatest"
+)
+
+type fakeClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestScheduler(t *testing.T) {
+	td := datatest.Jakarta
+	cfg := prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.AstronomicalTwilight(),
+		PreciseToSeconds:   true,
+	}
+
+	// Run the scheduler from the last day of the year
+	clock := &fakeClock{now: time.Date(2023, 12, 31, 0, 0, 0, 0, td.Timezone)}
+	scheduler := prayer.NewScheduler(cfg, clock, prayer.Alert{
+		Name:   "Pre-Fajr",
+		Prayer: prayer.Fajr,
+		Offset: -15 * time.Minute,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan prayer.Event)
+	runErr := make(chan error, 1)
+	go func() { runErr <- scheduler.Run(ctx, events) }()
+
+	// Prepare the expected events
+	schedules2023, err := prayer.Calculate(cfg, 2023)
+	assertNil(t, err, fmt.Sprintf("calculate 2023 has error: %v", err))
+	schedules2024, err := prayer.Calculate(cfg, 2024)
+	assertNil(t, err, fmt.Sprintf("calculate 2024 has error: %v", err))
+	var expected []prayer.Event
+	for _, s := range []prayer.Schedule{schedules2023[364], schedules2024[0]} {
+		expected = append(expected,
+			prayer.Event{Prayer: prayer.Fajr, Alert: "Pre-Fajr", Time: s.Fajr.Add(-15 * time.Minute)},
+			prayer.Event{Prayer: prayer.Fajr, Time: s.Fajr},
+			prayer.Event{Prayer: prayer.Sunrise, Time: s.Sunrise},
+			prayer.Event{Prayer: prayer.Zuhr, Time: s.Zuhr},
+			prayer.Event{Prayer: prayer.Asr, Time: s.Asr},
+			prayer.Event{Prayer: prayer.Maghrib, Time: s.Maghrib},
+			prayer.Event{Prayer: prayer.Isha, Time: s.Isha})
+	}
+
+	// Compare the events
+	for _, e := range expected {
+		r := <-events
+		msg := fmt.Sprintf("event: want %s %q at %s, got %s %q at %s",
+			e.Prayer, e.Alert, e.Time, r.Prayer, r.Alert, r.Time)
+		assertEqual(t, e.Prayer, r.Prayer, msg)
+		assertEqual(t, e.Alert, r.Alert, msg)
+		assertEqual(t, true, e.Time.Equal(r.Time), msg)
+	}
+
+	// After cancelled, the channel is closed so range loop can stop
+	cancel()
+	for range events {
+	}
+	assertEqual(t, context.Canceled, <-runErr, "run should return cancelled error")
+}
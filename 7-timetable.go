@@ -0,0 +1,191 @@
+package prayer
+
+import (
+	"time"
+)
+
+// TimetableAlignment specify how the days are grouped into blocks of stabilized
+// timetable.
+type TimetableAlignment int
+
+const (
+	// AlignDays groups the days into blocks of N days, counted from 1970-01-01.
+	AlignDays TimetableAlignment = iota
+
+	// AlignCalendarWeek groups the days into blocks of N calendar weeks that start
+	// on Sunday.
+	AlignCalendarWeek
+
+	// AlignISOWeek groups the days into blocks of N ISO weeks that start on Monday.
+	// The blocks are restarted at the beginning of each ISO year.
+	AlignISOWeek
+
+	// AlignHijriMonth groups the days into blocks of N Hijri months, using the Hijri
+	// date in the schedules. If the schedules are calculated without Hijri date, it
+	// will use the tabular calendar.
+	AlignHijriMonth
+)
+
+// TimetableConfig is configuration for creating stabilized timetable.
+type TimetableConfig struct {
+	// BlockSize is the number of units (days, weeks or months depending on the
+	// alignment) in each block. If not specified, it will use 7 for `AlignDays`
+	// and 1 for the others.
+	BlockSize int
+
+	// Alignment specify how the blocks are aligned.
+	Alignment TimetableAlignment
+}
+
+// TimetableEntry is the stabilized schedule for a day.
+type TimetableEntry struct {
+	// Schedule is the stabilized schedule.
+	Schedule
+
+	// Precise is the original schedule before stabilized.
+	Precise Schedule
+
+	// IsStabilized specify whether the stabilized schedule is different with the
+	// precise schedule.
+	IsStabilized bool
+}
+
+// Stabilize creates timetable where the times are held constant within each block
+// of days, which makes it easier to read and safe to follow. For starting times
+// (Fajr, Zuhr, Asr, Maghrib and Isha) it uses the latest time in the block, while
+// for ending boundary (sunrise) it uses the earliest time in the block.
+func Stabilize(schedules []Schedule, cfg TimetableConfig) []TimetableEntry {
+	// Apply default config
+	if cfg.BlockSize <= 0 {
+		cfg.BlockSize = 1
+		if cfg.Alignment == AlignDays {
+			cfg.BlockSize = 7
+		}
+	}
+
+	// Group the schedules into blocks
+	var blocks [][]int
+	var lastKey int
+	for i, s := range schedules {
+		key := timetableBlockKey(s, cfg)
+		if i == 0 || key != lastKey {
+			blocks = append(blocks, nil)
+		}
+
+		lastIdx := len(blocks) - 1
+		blocks[lastIdx] = append(blocks[lastIdx], i)
+		lastKey = key
+	}
+
+	// Stabilize each block
+	entries := make([]TimetableEntry, len(schedules))
+	for _, block := range blocks {
+		// Find the latest and earliest wall clock in the block
+		var fajr, zuhr, asr, maghrib, isha, sunrise wallTimes
+		for _, i := range block {
+			s := schedules[i]
+			fajr.add(s.Zuhr, s.Fajr)
+			sunrise.add(s.Zuhr, s.Sunrise)
+			zuhr.add(s.Zuhr, s.Zuhr)
+			asr.add(s.Zuhr, s.Asr)
+			maghrib.add(s.Zuhr, s.Maghrib)
+			isha.add(s.Zuhr, s.Isha)
+		}
+
+		// Apply it to each day
+		for _, i := range block {
+			precise := schedules[i]
+			s := precise
+			s.Fajr = fajr.latest(s.Zuhr, s.Fajr)
+			s.Sunrise = sunrise.earliest(s.Zuhr, s.Sunrise)
+			s.Zuhr = zuhr.latest(s.Zuhr, s.Zuhr)
+			s.Asr = asr.latest(s.Zuhr, s.Asr)
+			s.Maghrib = maghrib.latest(s.Zuhr, s.Maghrib)
+			s.Isha = isha.latest(s.Zuhr, s.Isha)
+
+			entries[i] = TimetableEntry{
+				Schedule:     s,
+				Precise:      precise,
+				IsStabilized: !sameSchedule(s, precise),
+			}
+		}
+	}
+
+	return entries
+}
+
+func sameSchedule(a, b Schedule) bool {
+	return a.Fajr.Equal(b.Fajr) &&
+		a.Sunrise.Equal(b.Sunrise) &&
+		a.Zuhr.Equal(b.Zuhr) &&
+		a.Asr.Equal(b.Asr) &&
+		a.Maghrib.Equal(b.Maghrib) &&
+		a.Isha.Equal(b.Isha)
+}
+
+func timetableBlockKey(s Schedule, cfg TimetableConfig) int {
+	t := s.Zuhr
+	switch cfg.Alignment {
+	case AlignCalendarWeek:
+		// 1970-01-04 is Sunday, so shift the Julian day number accordingly
+		days := julianDayNumber(t) - julianDayNumber(time.Date(1970, 1, 4, 0, 0, 0, 0, time.UTC))
+		return floorDiv(days, 7*cfg.BlockSize)
+	case AlignISOWeek:
+		year, week := t.ISOWeek()
+		return year*100 + (week-1)/cfg.BlockSize
+	case AlignHijriMonth:
+		year, month := s.Hijri.Year, s.Hijri.Month
+		if s.Hijri.IsZero() {
+			year, month, _ = tabularHijriDate(t)
+		}
+		return floorDiv(year*12+month-1, cfg.BlockSize)
+	default:
+		days := julianDayNumber(t) - julianDayNumber(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))
+		return floorDiv(days, cfg.BlockSize)
+	}
+}
+
+// wallTimes collects the wall clock of times, relative to the midnight of their
+// schedule's date. Wall clock is used instead of absolute duration so the times
+// can be moved between days with different UTC offset.
+type wallTimes struct {
+	min, max time.Duration
+	exist    bool
+}
+
+func (wt *wallTimes) add(base, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+
+	offset := dayClockTime(base, t)
+	if !wt.exist || offset < wt.min {
+		wt.min = offset
+	}
+	if !wt.exist || offset > wt.max {
+		wt.max = offset
+	}
+	wt.exist = true
+}
+
+func (wt wallTimes) latest(base, t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return atClockTime(base, wt.max)
+}
+
+func (wt wallTimes) earliest(base, t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return atClockTime(base, wt.min)
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
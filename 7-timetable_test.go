@@ -0,0 +1,72 @@
+package prayer_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+	"github.com/hablullah/go-prayer/internal/datatest"
+)
+
+func TestStabilize(t *testing.T) {
+	td := datatest.Jakarta
+	schedules, err := prayer.Calculate(prayer.Config{
+		Latitude:           td.Latitude,
+		Longitude:          td.Longitude,
+		Timezone:           td.Timezone,
+		TwilightConvention: prayer.AstronomicalTwilight(),
+	}, 2023)
+	assertNil(t, err, "calculate schedules error")
+
+	entries := prayer.Stabilize(schedules, prayer.TimetableConfig{
+		BlockSize: 1,
+		Alignment: prayer.AlignISOWeek,
+	})
+
+	for i, e := range entries {
+		// Stabilized start times never earlier than precise times, and sunrise never later
+		msg := fmt.Sprintf("stabilized schedule %s is not safe", e.Date)
+		assertEqual(t, false, e.Fajr.Before(e.Precise.Fajr), msg)
+		assertEqual(t, false, e.Sunrise.After(e.Precise.Sunrise), msg)
+		assertEqual(t, false, e.Zuhr.Before(e.Precise.Zuhr), msg)
+		assertEqual(t, false, e.Asr.Before(e.Precise.Asr), msg)
+		assertEqual(t, false, e.Maghrib.Before(e.Precise.Maghrib), msg)
+		assertEqual(t, false, e.Isha.Before(e.Precise.Isha), msg)
+
+		// Every days in the same ISO week have the same times
+		_, week := e.Zuhr.ISOWeek()
+		if i > 0 {
+			prev := entries[i-1]
+			if _, prevWeek := prev.Zuhr.ISOWeek(); prevWeek == week {
+				msg = fmt.Sprintf("stabilized schedule %s differs from %s", e.Date, prev.Date)
+				assertEqual(t, prev.Fajr.Format("15:04"), e.Fajr.Format("15:04"), msg)
+				assertEqual(t, prev.Sunrise.Format("15:04"), e.Sunrise.Format("15:04"), msg)
+				assertEqual(t, prev.Isha.Format("15:04"), e.Isha.Format("15:04"), msg)
+			}
+		}
+	}
+
+	// Blocks of days are counted from 1970-01-01, so the days in the same block
+	// must have the same times
+	entries = prayer.Stabilize(schedules, prayer.TimetableConfig{
+		BlockSize: 7,
+		Alignment: prayer.AlignDays,
+	})
+
+	epoch := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, e := range entries {
+		if i == 0 {
+			continue
+		}
+
+		date, _ := time.Parse("2006-01-02", e.Date)
+		days := int(date.Sub(epoch).Hours() / 24)
+		prev := entries[i-1]
+		msg := fmt.Sprintf("block of %s is not aligned to 1970-01-01", e.Date)
+		if days%7 != 0 {
+			assertEqual(t, prev.Fajr.Format("15:04"), e.Fajr.Format("15:04"), msg)
+			assertEqual(t, prev.Isha.Format("15:04"), e.Isha.Format("15:04"), msg)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package prayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// configFile is the representation of Config in JSON and YAML, where the twilight
+// convention and high latitude adapter are referred by their registered ID.
+type configFile struct {
+	Latitude            float64          `json:"latitude" yaml:"latitude"`
+	Longitude           float64          `json:"longitude" yaml:"longitude"`
+	Elevation           float64          `json:"elevation,omitempty" yaml:"elevation,omitempty"`
+	Timezone            string           `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	TwilightConvention  *conventionValue `json:"twilight_convention,omitempty" yaml:"twilight_convention,omitempty"`
+	AsrConvention       AsrConvention    `json:"asr_convention" yaml:"asr_convention"`
+	HighLatitudeAdapter string           `json:"high_latitude_adapter,omitempty" yaml:"high_latitude_adapter,omitempty"`
+	EstimateMissingAsr  bool             `json:"estimate_missing_asr,omitempty" yaml:"estimate_missing_asr,omitempty"`
+	Corrections         correctionsFile  `json:"corrections" yaml:"corrections"`
+	RamadanCorrections  *correctionsFile `json:"ramadan_corrections,omitempty" yaml:"ramadan_corrections,omitempty"`
+	PreciseToSeconds    bool             `json:"precise_to_seconds,omitempty" yaml:"precise_to_seconds,omitempty"`
+	Rounding            *roundingFile    `json:"rounding,omitempty" yaml:"rounding,omitempty"`
+	HijriCalendar       HijriCalendar    `json:"hijri_calendar" yaml:"hijri_calendar"`
+	HijriOffset         int              `json:"hijri_offset,omitempty" yaml:"hijri_offset,omitempty"`
+	IncludeHijri        bool             `json:"include_hijri,omitempty" yaml:"include_hijri,omitempty"`
+}
+
+type correctionsFile struct {
+	Fajr    textDuration `json:"fajr,omitempty" yaml:"fajr,omitempty"`
+	Sunrise textDuration `json:"sunrise,omitempty" yaml:"sunrise,omitempty"`
+	Zuhr    textDuration `json:"zuhr,omitempty" yaml:"zuhr,omitempty"`
+	Asr     textDuration `json:"asr,omitempty" yaml:"asr,omitempty"`
+	Maghrib textDuration `json:"maghrib,omitempty" yaml:"maghrib,omitempty"`
+	Isha    textDuration `json:"isha,omitempty" yaml:"isha,omitempty"`
+}
+
+func newCorrectionsFile(sc ScheduleCorrections) correctionsFile {
+	return correctionsFile{
+		Fajr:    textDuration(sc.Fajr),
+		Sunrise: textDuration(sc.Sunrise),
+		Zuhr:    textDuration(sc.Zuhr),
+		Asr:     textDuration(sc.Asr),
+		Maghrib: textDuration(sc.Maghrib),
+		Isha:    textDuration(sc.Isha),
+	}
+}
+
+func (cf correctionsFile) toCorrections() ScheduleCorrections {
+	return ScheduleCorrections{
+		Fajr:    time.Duration(cf.Fajr),
+		Sunrise: time.Duration(cf.Sunrise),
+		Zuhr:    time.Duration(cf.Zuhr),
+		Asr:     time.Duration(cf.Asr),
+		Maghrib: time.Duration(cf.Maghrib),
+		Isha:    time.Duration(cf.Isha),
+	}
+}
+
+type roundingFile struct {
+	Fajr    roundingRuleFile `json:"fajr" yaml:"fajr"`
+	Sunrise roundingRuleFile `json:"sunrise" yaml:"sunrise"`
+	Zuhr    roundingRuleFile `json:"zuhr" yaml:"zuhr"`
+	Asr     roundingRuleFile `json:"asr" yaml:"asr"`
+	Maghrib roundingRuleFile `json:"maghrib" yaml:"maghrib"`
+	Isha    roundingRuleFile `json:"isha" yaml:"isha"`
+}
+
+type roundingRuleFile struct {
+	Method      RoundingMethod `json:"method" yaml:"method"`
+	Granularity textDuration   `json:"granularity,omitempty" yaml:"granularity,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The twilight convention is encoded using
+// its registered ID, or as object of angles if it's not registered. The high latitude
+// adapter must be registered (see `RegisterAdapter`), otherwise it will return error.
+// Since they are functions, `TimezoneLookup` and `DailyCorrections` can't be encoded,
+// so it will return error as well if they are specified.
+func (cfg Config) MarshalJSON() ([]byte, error) {
+	cf, err := cfg.toFile()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(cf)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (cfg *Config) UnmarshalJSON(data []byte) error {
+	var cf configFile
+	if err := json.Unmarshal(data, &cf); err != nil {
+		return err
+	}
+	return cfg.fromFile(cf)
+}
+
+// MarshalYAML implements the marshaler interface of the common YAML packages, e.g.
+// gopkg.in/yaml.v3, without depending on them. See `MarshalJSON` for the details.
+func (cfg Config) MarshalYAML() (any, error) {
+	return cfg.toFile()
+}
+
+// UnmarshalYAML implements the obsolete unmarshaler interface of the common YAML
+// packages, e.g. gopkg.in/yaml.v3, without depending on them.
+func (cfg *Config) UnmarshalYAML(unmarshal func(any) error) error {
+	var cf configFile
+	if err := unmarshal(&cf); err != nil {
+		return err
+	}
+	return cfg.fromFile(cf)
+}
+
+func (cfg Config) toFile() (configFile, error) {
+	if cfg.TimezoneLookup != nil {
+		return configFile{}, fmt.Errorf("timezone lookup can't be encoded")
+	}
+
+	if cfg.DailyCorrections != nil {
+		return configFile{}, fmt.Errorf("daily corrections can't be encoded")
+	}
+
+	cf := configFile{
+		Latitude:           cfg.Latitude,
+		Longitude:          cfg.Longitude,
+		Elevation:          cfg.Elevation,
+		AsrConvention:      cfg.AsrConvention,
+		EstimateMissingAsr: cfg.EstimateMissingAsr,
+		PreciseToSeconds:   cfg.PreciseToSeconds,
+		HijriCalendar:      cfg.HijriCalendar,
+		HijriOffset:        cfg.HijriOffset,
+		IncludeHijri:       cfg.IncludeHijri,
+		Corrections:        newCorrectionsFile(cfg.Corrections),
+	}
+
+	if cfg.RamadanCorrections != (ScheduleCorrections{}) {
+		rc := newCorrectionsFile(cfg.RamadanCorrections)
+		cf.RamadanCorrections = &rc
+	}
+
+	if cfg.Timezone != nil {
+		cf.Timezone = cfg.Timezone.String()
+	}
+
+	if cfg.TwilightConvention != nil {
+		cf.TwilightConvention = &conventionValue{cfg.TwilightConvention}
+	}
+
+	if cfg.HighLatitudeAdapter != nil {
+		id, exist := adapterID(cfg.HighLatitudeAdapter)
+		if !exist {
+			return configFile{}, fmt.Errorf("high latitude adapter is not registered")
+		}
+		cf.HighLatitudeAdapter = id
+	}
+
+	if r := cfg.Rounding; r != nil {
+		cf.Rounding = &roundingFile{
+			Fajr:    roundingRuleFile{r.Fajr.Method, textDuration(r.Fajr.Granularity)},
+			Sunrise: roundingRuleFile{r.Sunrise.Method, textDuration(r.Sunrise.Granularity)},
+			Zuhr:    roundingRuleFile{r.Zuhr.Method, textDuration(r.Zuhr.Granularity)},
+			Asr:     roundingRuleFile{r.Asr.Method, textDuration(r.Asr.Granularity)},
+			Maghrib: roundingRuleFile{r.Maghrib.Method, textDuration(r.Maghrib.Granularity)},
+			Isha:    roundingRuleFile{r.Isha.Method, textDuration(r.Isha.Granularity)},
+		}
+	}
+
+	return cf, nil
+}
+
+func (cfg *Config) fromFile(cf configFile) error {
+	newCfg := Config{
+		Latitude:           cf.Latitude,
+		Longitude:          cf.Longitude,
+		Elevation:          cf.Elevation,
+		AsrConvention:      cf.AsrConvention,
+		EstimateMissingAsr: cf.EstimateMissingAsr,
+		PreciseToSeconds:   cf.PreciseToSeconds,
+		HijriCalendar:      cf.HijriCalendar,
+		HijriOffset:        cf.HijriOffset,
+		IncludeHijri:       cf.IncludeHijri,
+		Corrections:        cf.Corrections.toCorrections(),
+	}
+
+	if cf.RamadanCorrections != nil {
+		newCfg.RamadanCorrections = cf.RamadanCorrections.toCorrections()
+	}
+
+	if cf.Timezone != "" {
+		tz, err := time.LoadLocation(cf.Timezone)
+		if err != nil {
+			return err
+		}
+		newCfg.Timezone = tz
+	}
+
+	if cf.TwilightConvention != nil {
+		newCfg.TwilightConvention = cf.TwilightConvention.TwilightConvention
+	}
+
+	if cf.HighLatitudeAdapter != "" {
+		adapter, exist := LookupAdapter(cf.HighLatitudeAdapter)
+		if !exist {
+			return fmt.Errorf("unknown high latitude adapter %q", cf.HighLatitudeAdapter)
+		}
+		newCfg.HighLatitudeAdapter = adapter
+	}
+
+	if r := cf.Rounding; r != nil {
+		newCfg.Rounding = &RoundingPolicy{
+			Fajr:    Rounding{r.Fajr.Method, time.Duration(r.Fajr.Granularity)},
+			Sunrise: Rounding{r.Sunrise.Method, time.Duration(r.Sunrise.Granularity)},
+			Zuhr:    Rounding{r.Zuhr.Method, time.Duration(r.Zuhr.Granularity)},
+			Asr:     Rounding{r.Asr.Method, time.Duration(r.Asr.Granularity)},
+			Maghrib: Rounding{r.Maghrib.Method, time.Duration(r.Maghrib.Granularity)},
+			Isha:    Rounding{r.Isha.Method, time.Duration(r.Isha.Granularity)},
+		}
+	}
+
+	*cfg = newCfg
+	return nil
+}
+
+// conventionValue is twilight convention that encoded using its registered ID, or
+// as object of angles if it's not registered.
+type conventionValue struct {
+	*TwilightConvention
+}
+
+type conventionObject struct {
+	FajrAngle       float64      `json:"fajr_angle" yaml:"fajr_angle"`
+	IshaAngle       float64      `json:"isha_angle" yaml:"isha_angle"`
+	MaghribDuration textDuration `json:"maghrib_duration,omitempty" yaml:"maghrib_duration,omitempty"`
+}
+
+func (cv conventionValue) encode() any {
+	if id, exist := conventionID(cv.TwilightConvention); exist {
+		return id
+	}
+
+	return conventionObject{
+		FajrAngle:       cv.FajrAngle,
+		IshaAngle:       cv.IshaAngle,
+		MaghribDuration: textDuration(cv.MaghribDuration),
+	}
+}
+
+func (cv *conventionValue) decode(id string, obj *conventionObject) error {
+	if obj != nil {
+		cv.TwilightConvention = &TwilightConvention{
+			FajrAngle:       obj.FajrAngle,
+			IshaAngle:       obj.IshaAngle,
+			MaghribDuration: time.Duration(obj.MaghribDuration),
+		}
+		return nil
+	}
+
+	tc, exist := LookupConvention(id)
+	if !exist {
+		return fmt.Errorf("unknown twilight convention %q", id)
+	}
+	cv.TwilightConvention = tc
+	return nil
+}
+
+func (cv conventionValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cv.encode())
+}
+
+func (cv *conventionValue) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		return cv.decode(id, nil)
+	}
+
+	var obj conventionObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	return cv.decode("", &obj)
+}
+
+func (cv conventionValue) MarshalYAML() (any, error) {
+	return cv.encode(), nil
+}
+
+func (cv *conventionValue) UnmarshalYAML(unmarshal func(any) error) error {
+	var id string
+	if err := unmarshal(&id); err == nil {
+		return cv.decode(id, nil)
+	}
+
+	var obj conventionObject
+	if err := unmarshal(&obj); err != nil {
+		return err
+	}
+	return cv.decode("", &obj)
+}
+
+// textDuration is duration that encoded as text, e.g. "1h30m" or "-2m".
+type textDuration time.Duration
+
+func (d textDuration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *textDuration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = textDuration(duration)
+	return nil
+}
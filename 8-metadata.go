@@ -0,0 +1,383 @@
+package prayer
+
+import "math"
+
+// ConventionInfo is the descriptive metadata of a twilight convention, useful for
+// rendering convention picker in UI.
+type ConventionInfo struct {
+	// ID is the registered ID of the convention.
+	ID string
+
+	// Name is the display name of the convention.
+	Name string
+
+	// Description is the short description of the convention.
+	Description string
+
+	// Authority is the institution that issued the convention.
+	Authority string
+
+	// Regions is the list of regions or countries where the convention is used.
+	Regions []string
+
+	// SourceURL is the link to the reference of the convention.
+	SourceURL string
+
+	// MinLatitude and MaxLatitude is the range of absolute latitude where the Sun
+	// reaches the twilight angles for the entire year, so the convention can be used
+	// without high latitude adapter. If not specified in the registered metadata,
+	// MaxLatitude is calculated using `TwilightConvention.MaxLatitude`.
+	MinLatitude float64
+	MaxLatitude float64
+
+	// RequiresSunriseSunset specify whether the convention requires sunrise and
+	// sunset to exist in a day, e.g. when Isha is fixed after Maghrib.
+	RequiresSunriseSunset bool
+}
+
+// AdapterInfo is the descriptive metadata of a high latitude adapter, useful for
+// rendering adapter picker and warnings in UI.
+type AdapterInfo struct {
+	// ID is the registered ID of the adapter.
+	ID string
+
+	// Name is the display name of the adapter.
+	Name string
+
+	// Description is the short description of the adapter.
+	Description string
+
+	// SourceURL is the link to the reference of the adapter.
+	SourceURL string
+
+	// MinLatitude and MaxLatitude is the range of absolute latitude where the adapter
+	// is suitable. Below MinLatitude the adapter is usually not needed.
+	MinLatitude float64
+	MaxLatitude float64
+
+	// RequiresSunriseSunset specify whether the adapter requires sunrise and sunset
+	// to exist in a day. If true, the adapter is not suitable for area in extreme
+	// latitudes (>=65 degrees).
+	RequiresSunriseSunset bool
+}
+
+// MaxLatitude returns the maximum absolute latitude where the Sun still reaches the
+// twilight angles of the convention for the entire year, so the convention works
+// without high latitude adapter.
+func (tc TwilightConvention) MaxLatitude() float64 {
+	const obliquity = 23.44
+	angle := tc.FajrAngle
+	if tc.MaghribDuration == 0 {
+		angle = math.Max(angle, tc.IshaAngle)
+	}
+	return 90 - obliquity - angle
+}
+
+// The references that cited in README.
+const (
+	praytimesMethodsURL = "http://praytimes.org/wiki/Calculation_Methods"
+	islamicFinderURL    = "http://www.islamicfinder.us/index.php/api/index"
+	muslimProURL        = "https://www.muslimpro.com/en/prayer-times"
+	praytimesHighLatURL = "http://praytimes.org/calculation"
+	prayerTimesDKURL    = "https://www.prayertimes.dk/fatawa.html"
+	icopURL             = "https://www.astronomycenter.net/latitude.html?l=en"
+	islamicityURL       = "https://www.islamicity.com/prayertimes/Salat.pdf"
+	islamOnlineURL      = "https://fiqh.islamonline.net/en/praying-and-fasting-at-high-latitudes/"
+	tarabishyURL        = "https://www.astronomycenter.net/pdf/tarabishyshigh_2014.pdf"
+)
+
+var conventionInfos = []ConventionInfo{{
+	ID:          "astronomical",
+	Name:        "Astronomical Twilight",
+	Description: "Fajr and Isha when the Sun is 18° below horizon, i.e. the astronomical twilight.",
+	Regions:     []string{"Worldwide"},
+	SourceURL:   "https://en.wikipedia.org/wiki/Twilight#Astronomical_twilight",
+}, {
+	ID:          "mwl",
+	Name:        "Muslim World League",
+	Description: "Fajr at 18° and Isha at 17°. Default in most calculators.",
+	Authority:   "Muslim World League",
+	Regions:     []string{"Europe", "Far East", "Parts of America"},
+	SourceURL:   praytimesMethodsURL,
+}, {
+	ID:          "isna",
+	Name:        "ISNA",
+	Description: "Fajr and Isha at 15°.",
+	Authority:   "Islamic Society of North America",
+	Regions:     []string{"United States", "Canada"},
+	SourceURL:   praytimesMethodsURL,
+}, {
+	ID:                    "umm_al_qura",
+	Name:                  "Umm al-Qura",
+	Description:           "Fajr at 18.5° and Isha fixed at 90 minutes after Maghrib.",
+	Authority:             "Umm al-Qura University, Makkah",
+	Regions:               []string{"Saudi Arabia"},
+	SourceURL:             praytimesMethodsURL,
+	RequiresSunriseSunset: true,
+}, {
+	ID:                    "gulf",
+	Name:                  "Gulf Region",
+	Description:           "Fajr at 19.5° and Isha fixed at 90 minutes after Maghrib.",
+	Regions:               []string{"United Arab Emirates", "Kuwait", "Gulf region"},
+	SourceURL:             islamicFinderURL,
+	RequiresSunriseSunset: true,
+}, {
+	ID:          "algerian",
+	Name:        "Algeria",
+	Description: "Fajr at 18° and Isha at 17°.",
+	Authority:   "Algerian Ministry of Religious Affairs and Wakfs",
+	Regions:     []string{"Algeria"},
+	SourceURL:   islamicFinderURL,
+}, {
+	ID:          "karachi",
+	Name:        "Karachi",
+	Description: "Fajr and Isha at 18°.",
+	Authority:   "University of Islamic Sciences, Karachi",
+	Regions:     []string{"Pakistan", "Afghanistan", "Bangladesh", "India"},
+	SourceURL:   praytimesMethodsURL,
+}, {
+	ID:          "diyanet",
+	Name:        "Diyanet",
+	Description: "Fajr at 18° and Isha at 17°.",
+	Authority:   "Diyanet İşleri Başkanlığı",
+	Regions:     []string{"Turkey", "Turkish communities in Europe"},
+	SourceURL:   "https://namazvakitleri.diyanet.gov.tr",
+}, {
+	ID:          "egypt",
+	Name:        "Egypt",
+	Description: "Fajr at 19.5° and Isha at 17.5°.",
+	Authority:   "Egyptian General Authority of Survey",
+	Regions:     []string{"Africa", "Syria", "Lebanon"},
+	SourceURL:   praytimesMethodsURL,
+}, {
+	ID:          "egypt_bis",
+	Name:        "Egypt Bis",
+	Description: "Fajr at 20° and Isha at 18°.",
+	Authority:   "Egyptian General Authority of Survey",
+	Regions:     []string{"Egypt"},
+	SourceURL:   muslimProURL,
+}, {
+	ID:          "kemenag",
+	Name:        "Kemenag",
+	Description: "Fajr at 20° and Isha at 18°.",
+	Authority:   "Kementerian Agama Republik Indonesia",
+	Regions:     []string{"Indonesia"},
+	SourceURL:   "https://bimasislam.kemenag.go.id",
+}, {
+	ID:          "muis",
+	Name:        "MUIS",
+	Description: "Fajr at 20° and Isha at 18°.",
+	Authority:   "Majlis Ugama Islam Singapura",
+	Regions:     []string{"Singapore"},
+	SourceURL:   "https://www.muis.gov.sg",
+}, {
+	ID:          "jakim",
+	Name:        "JAKIM",
+	Description: "Fajr at 20° and Isha at 18°.",
+	Authority:   "Jabatan Kemajuan Islam Malaysia",
+	Regions:     []string{"Malaysia"},
+	SourceURL:   "https://www.e-solat.gov.my",
+}, {
+	ID:          "uoif",
+	Name:        "UOIF",
+	Description: "Fajr and Isha at 12°.",
+	Authority:   "Union Des Organisations Islamiques De France",
+	Regions:     []string{"France"},
+	SourceURL:   muslimProURL,
+}, {
+	ID:          "france15",
+	Name:        "France 15°",
+	Description: "Fajr and Isha at 15°.",
+	Regions:     []string{"France"},
+	SourceURL:   muslimProURL,
+}, {
+	ID:          "france18",
+	Name:        "France 18°",
+	Description: "Fajr and Isha at 18°.",
+	Regions:     []string{"France"},
+	SourceURL:   muslimProURL,
+}, {
+	ID:          "tunisia",
+	Name:        "Tunisia",
+	Description: "Fajr and Isha at 18°.",
+	Authority:   "Tunisian Ministry of Religious Affairs",
+	Regions:     []string{"Tunisia"},
+	SourceURL:   muslimProURL,
+}, {
+	ID:          "tehran",
+	Name:        "Tehran",
+	Description: "Fajr at 17.7° and Isha at 14°.",
+	Authority:   "Institute of Geophysics, University of Tehran",
+	Regions:     []string{"Iran"},
+	SourceURL:   praytimesMethodsURL,
+}, {
+	ID:          "jafari",
+	Name:        "Shia Ithna Ashari",
+	Description: "Fajr at 16° and Isha at 14°.",
+	Authority:   "Shia Ithna Ashari, Leva Institute, Qum",
+	Regions:     []string{"Shia communities worldwide"},
+	SourceURL:   praytimesMethodsURL,
+}}
+
+var adapterInfos = []AdapterInfo{{
+	ID:          "mecca",
+	Name:        "Follow Mecca",
+	Description: "Follow the schedule in Mecca during abnormal days, using transit time as the common point, with transition period before and after.",
+	SourceURL:   prayerTimesDKURL,
+	MinLatitude: 45,
+	MaxLatitude: 90,
+}, {
+	ID:          "always_mecca",
+	Name:        "Always Follow Mecca",
+	Description: "Follow the schedule in Mecca every day, using transit time as the common point.",
+	SourceURL:   prayerTimesDKURL,
+	MinLatitude: 45,
+	MaxLatitude: 90,
+}, {
+	ID:                    "local_relative_estimation",
+	Name:                  "Local Relative Estimation",
+	Description:           "Estimate Fajr and Isha in abnormal days using the average night percentage of the normal days.",
+	SourceURL:             icopURL,
+	MinLatitude:           45,
+	MaxLatitude:           65,
+	RequiresSunriseSunset: true,
+}, {
+	ID:          "nearest_day",
+	Name:        "Nearest Day",
+	Description: "Use the schedule of the last normal day for the entire abnormal period.",
+	SourceURL:   islamicityURL,
+	MinLatitude: 45,
+	MaxLatitude: 90,
+}, {
+	ID:                    "nearest_latitude",
+	Name:                  "Nearest Latitude",
+	Description:           "Estimate Fajr and Isha using the night percentage at 45° latitude for the entire year.",
+	SourceURL:             islamOnlineURL,
+	MinLatitude:           45,
+	MaxLatitude:           65,
+	RequiresSunriseSunset: true,
+}, {
+	ID:          "nearest_latitude_as_is",
+	Name:        "Nearest Latitude As Is",
+	Description: "Use the schedule at 45° latitude as it is for the entire year.",
+	SourceURL:   islamOnlineURL,
+	MinLatitude: 45,
+	MaxLatitude: 90,
+}, {
+	ID:          "shari_normal_day",
+	Name:        "Shari Normal Day",
+	Description: "Use the schedule at 45° latitude when the fasting duration is not between 10h17m and 17h36m.",
+	SourceURL:   tarabishyURL,
+	MinLatitude: 45,
+	MaxLatitude: 90,
+}, {
+	ID:          "angle_based",
+	Name:        "Angle Based",
+	Description: "Divide the night into parts depending on the twilight angle, e.g. Isha after 15/60 of the night for 15°.",
+	SourceURL:   praytimesHighLatURL,
+	MinLatitude: 45,
+	MaxLatitude: 90,
+}, {
+	ID:          "one_seventh_night",
+	Name:        "One Seventh of the Night",
+	Description: "Isha after the first seventh of the night, and Fajr at the start of the last seventh.",
+	SourceURL:   praytimesHighLatURL,
+	MinLatitude: 45,
+	MaxLatitude: 90,
+}, {
+	ID:          "middle_night",
+	Name:        "Middle of the Night",
+	Description: "Fajr and Isha at the middle of the night during abnormal periods.",
+	SourceURL:   praytimesHighLatURL,
+	MinLatitude: 45,
+	MaxLatitude: 90,
+}, {
+	ID:          "diyanet",
+	Name:        "Diyanet",
+	Description: "Estimate Fajr and Isha above 48° latitude using the night percentage at 48° latitude in the same day.",
+	MinLatitude: 48,
+	MaxLatitude: 90,
+}, {
+	ID:                    "fasting_cap_18h",
+	Name:                  "Fasting Cap (18 Hours)",
+	Description:           "Move Fajr later when the fasting duration exceeds 18 hours, with transition period before and after.",
+	MinLatitude:           45,
+	MaxLatitude:           65,
+	RequiresSunriseSunset: true,
+}, {
+	ID:                    "fasting_cap_19h",
+	Name:                  "Fasting Cap (19 Hours)",
+	Description:           "Move Fajr later when the fasting duration exceeds 19 hours, with transition period before and after.",
+	MinLatitude:           45,
+	MaxLatitude:           65,
+	RequiresSunriseSunset: true,
+}, {
+	ID:          "max_depression",
+	Name:        "Maximum Depression",
+	Description: "Fajr and Isha at the lowest point of the Sun in the night (solar midnight) when the Sun doesn't reach the twilight angle.",
+	MinLatitude: 45,
+	MaxLatitude: 90,
+}, {
+	ID:          "angle_reduction",
+	Name:        "Angle Reduction",
+	Description: "Reduce the twilight angle by 1° until it's reached in the night, down to 12°, smoothed across the days.",
+	MinLatitude: 45,
+	MaxLatitude: 60,
+}, {
+	ID:          "aqrab_al_bilad",
+	Name:        "Aqrab al-Bilad",
+	Description: "Follow the nearest latitude along the meridian that has normal days in each abnormal period, using transit time as the common point.",
+	MinLatitude: 45,
+	MaxLatitude: 90,
+}}
+
+func init() {
+	for _, info := range conventionInfos {
+		RegisterConventionInfo(info)
+	}
+
+	for _, info := range adapterInfos {
+		RegisterAdapterInfo(info)
+	}
+}
+
+// RegisterConventionInfo registers the metadata of a twilight convention, using the
+// ID in the metadata. If the ID already registered, it will be replaced.
+func RegisterConventionInfo(info ConventionInfo) {
+	defaultRegistry.Lock()
+	defer defaultRegistry.Unlock()
+	defaultRegistry.conventionInfos[info.ID] = info
+}
+
+// LookupConventionInfo returns the metadata of twilight convention with the
+// specified ID.
+func LookupConventionInfo(id string) (ConventionInfo, bool) {
+	defaultRegistry.RLock()
+	info, exist := defaultRegistry.conventionInfos[id]
+	defaultRegistry.RUnlock()
+
+	if exist && info.MaxLatitude == 0 {
+		if tc, tcExist := LookupConvention(id); tcExist {
+			info.MaxLatitude = tc.MaxLatitude()
+		}
+	}
+
+	return info, exist
+}
+
+// RegisterAdapterInfo registers the metadata of a high latitude adapter, using the
+// ID in the metadata. If the ID already registered, it will be replaced.
+func RegisterAdapterInfo(info AdapterInfo) {
+	defaultRegistry.Lock()
+	defer defaultRegistry.Unlock()
+	defaultRegistry.adapterInfos[info.ID] = info
+}
+
+// LookupAdapterInfo returns the metadata of high latitude adapter with the specified
+// ID.
+func LookupAdapterInfo(id string) (AdapterInfo, bool) {
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+	info, exist := defaultRegistry.adapterInfos[id]
+	return info, exist
+}
@@ -0,0 +1,176 @@
+package prayer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Recommendation is the conventions that recommended for a location.
+type Recommendation struct {
+	// ConventionID is the registered ID of the recommended twilight convention.
+	ConventionID string
+
+	// TwilightConvention is the recommended twilight convention.
+	TwilightConvention *TwilightConvention
+
+	// AsrConvention is the recommended Asr convention.
+	AsrConvention AsrConvention
+
+	// AdapterID is the registered ID of the recommended high latitude adapter. It
+	// will be empty if the adapter is not needed.
+	AdapterID string
+
+	// HighLatitudeAdapter is the recommended high latitude adapter. It will be nil
+	// if the adapter is not needed.
+	HighLatitudeAdapter HighLatitudeAdapter
+
+	// Explanation is the reasons behind the recommendation, in human readable text.
+	Explanation []string
+}
+
+// Apply returns copy of the config with the recommended conventions applied.
+func (r Recommendation) Apply(cfg Config) Config {
+	cfg.TwilightConvention = r.TwilightConvention
+	cfg.AsrConvention = r.AsrConvention
+	cfg.HighLatitudeAdapter = r.HighLatitudeAdapter
+	return cfg
+}
+
+// countryConventions maps ISO 3166-1 alpha-2 country code into the registered ID
+// of the twilight convention that conventionally used in that country.
+var countryConventions = map[string]string{
+	"US": "isna", "CA": "isna",
+	"SA": "umm_al_qura",
+	"AE": "gulf", "KW": "gulf", "QA": "gulf", "BH": "gulf", "OM": "gulf",
+	"DZ": "algerian",
+	"PK": "karachi", "AF": "karachi", "BD": "karachi", "IN": "karachi",
+	"TR": "diyanet",
+	"EG": "egypt", "SD": "egypt", "SS": "egypt", "LY": "egypt", "SY": "egypt", "LB": "egypt",
+	"ID": "kemenag",
+	"SG": "muis",
+	"MY": "jakim", "BN": "jakim",
+	"FR": "uoif",
+	"TN": "tunisia",
+	"IR": "tehran",
+}
+
+// hanafiCountries is countries where Hanafi school is conventionally used for Asr.
+var hanafiCountries = map[string]bool{
+	"PK": true, "AF": true, "BD": true, "IN": true,
+	"UZ": true, "TJ": true, "KZ": true, "KG": true, "TM": true,
+}
+
+// conventionRegion is rough bounding box of a region, used for recommending
+// convention when country code is not specified. Since the box might overlap the
+// neighboring countries, it doesn't claim any country code. Instead, it specifies
+// the Asr convention that conventionally used in the entire box.
+type conventionRegion struct {
+	Name          string
+	ConventionID  string
+	AsrConvention AsrConvention
+	MinLatitude   float64
+	MaxLatitude   float64
+	MinLongitude  float64
+	MaxLongitude  float64
+}
+
+// conventionRegions is checked in order, so the smaller region must be put before
+// the larger region that overlaps it. The boxes avoid the areas where the convention
+// is uncertain, e.g. South India where Shafii school is common as well, and the
+// border between United States and Mexico, so those areas use the default.
+var conventionRegions = []conventionRegion{
+	{"Singapore", "muis", Shafii, 1.15, 1.47, 103.6, 104.1},
+	{"Malaysia", "jakim", Shafii, 0.8, 7.5, 99.5, 119.5},
+	{"Indonesia", "kemenag", Shafii, -11, 6, 95, 141.1},
+	{"Turkey", "diyanet", Shafii, 35.8, 42.1, 25.6, 44.8},
+	{"Egypt", "egypt", Shafii, 22, 31.7, 24.7, 35},
+	{"Kuwait", "gulf", Shafii, 28.5, 30.1, 46.5, 48.5},
+	{"Bahrain and Qatar", "gulf", Shafii, 24.4, 26.4, 50.4, 51.7},
+	{"United Arab Emirates", "gulf", Shafii, 22.6, 26.4, 51.7, 56.5},
+	{"Oman", "gulf", Shafii, 16.6, 24.9, 55, 59.9},
+	{"Southern Iran", "tehran", Shafii, 26.5, 30, 50, 56.5},
+	{"Southern Iran", "tehran", Shafii, 25, 30, 56.5, 63.4},
+	{"Arabian Peninsula", "umm_al_qura", Shafii, 12, 30, 34.5, 60},
+	{"Iran", "tehran", Shafii, 25, 39.8, 44, 63.4},
+	{"Pakistan and Afghanistan", "karachi", Hanafi, 23.5, 38.5, 60.5, 75.5},
+	{"North India and Bangladesh", "karachi", Hanafi, 17, 35.5, 68, 92.3},
+	{"Northern United States", "isna", Shafii, 32.8, 49, -125, -66.9},
+	{"Southeastern United States", "isna", Shafii, 24.5, 32.8, -97, -79.9},
+	{"Central Texas", "isna", Shafii, 28.8, 32.8, -100, -97},
+	{"Canada", "isna", Shafii, 41.6, 60, -141, -52.6},
+	{"Northern Canada", "isna", Shafii, 60, 84, -141, -60},
+	{"Alaska", "isna", Shafii, 51, 72, -170, -141},
+}
+
+// Recommend returns the conventions that conventionally used in the specified
+// location. The country code is optional ISO 3166-1 alpha-2 code; if it's empty the
+// country will be roughly estimated from the coordinate.
+//
+// The high latitude adapter is recommended using the latitude thresholds in the
+// adapter docs: no adapter below 45 degrees, `NearestLatitude` below 65 degrees since
+// it requires sunrise and sunset, and `Mecca` for the extreme latitudes.
+func Recommend(latitude, longitude float64, countryCode string) Recommendation {
+	var r Recommendation
+	countryCode = strings.ToUpper(strings.TrimSpace(countryCode))
+	useHanafi := hanafiCountries[countryCode]
+
+	// Recommend the twilight convention
+	if id, exist := countryConventions[countryCode]; exist {
+		r.ConventionID = id
+		r.Explanation = append(r.Explanation, fmt.Sprintf(
+			"Convention %q is conventionally used in country %s.", id, countryCode))
+	} else if region, exist := findConventionRegion(latitude, longitude); exist && countryCode == "" {
+		r.ConventionID = region.ConventionID
+		useHanafi = region.AsrConvention == Hanafi
+		r.Explanation = append(r.Explanation, fmt.Sprintf(
+			"Location is roughly within %s where convention %q is conventionally used.",
+			region.Name, region.ConventionID))
+	} else {
+		r.ConventionID = "mwl"
+		r.Explanation = append(r.Explanation,
+			"No specific convention is known for this location, so it uses Muslim World League which is the default in most calculators.")
+	}
+	r.TwilightConvention, _ = LookupConvention(r.ConventionID)
+
+	// Recommend the Asr convention
+	if useHanafi {
+		r.AsrConvention = Hanafi
+		r.Explanation = append(r.Explanation, "Asr uses Hanafi school which is conventionally followed in this area.")
+	} else {
+		r.AsrConvention = Shafii
+		r.Explanation = append(r.Explanation, "Asr uses Shafii school which is followed by the majority.")
+	}
+
+	// Recommend the high latitude adapter
+	absLatitude := math.Abs(latitude)
+	switch {
+	case absLatitude < 45:
+		r.Explanation = append(r.Explanation, "Location is below 45° latitude where the twilight always occurs, "+
+			"so high latitude adapter is not needed.")
+	case absLatitude < 65:
+		r.AdapterID = "nearest_latitude"
+		r.Explanation = append(r.Explanation, "Location is in high latitude (>=45°) where the twilight might persist through the night, "+
+			"so it uses the night percentage from 45° latitude.")
+	default:
+		r.AdapterID = "mecca"
+		r.Explanation = append(r.Explanation, "Location is in extreme latitude (>=65°) where the Sun might not rise or set, "+
+			"so it follows the schedule in Mecca during the abnormal periods.")
+	}
+
+	if r.AdapterID != "" {
+		r.HighLatitudeAdapter, _ = LookupAdapter(r.AdapterID)
+	}
+
+	return r
+}
+
+func findConventionRegion(latitude, longitude float64) (conventionRegion, bool) {
+	for _, region := range conventionRegions {
+		if latitude >= region.MinLatitude && latitude <= region.MaxLatitude &&
+			longitude >= region.MinLongitude && longitude <= region.MaxLongitude {
+			return region, true
+		}
+	}
+	return conventionRegion{}, false
+}
@@ -0,0 +1,183 @@
+package prayer
+
+import (
+	"reflect"
+	"regexp"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// registry maps stable string IDs into twilight conventions and high latitude
+// adapters, so they can be chosen from config files or query parameters.
+type registry struct {
+	sync.RWMutex
+	conventionIDs   []string
+	conventions     map[string]func() *TwilightConvention
+	conventionInfos map[string]ConventionInfo
+	adapterIDs      []string
+	adapters        map[string]HighLatitudeAdapter
+	adapterInfos    map[string]AdapterInfo
+}
+
+var defaultRegistry = &registry{
+	conventions:     map[string]func() *TwilightConvention{},
+	conventionInfos: map[string]ConventionInfo{},
+	adapters:        map[string]HighLatitudeAdapter{},
+	adapterInfos:    map[string]AdapterInfo{},
+}
+
+func init() {
+	RegisterConvention("astronomical", AstronomicalTwilight)
+	RegisterConvention("mwl", MWL)
+	RegisterConvention("isna", ISNA)
+	RegisterConvention("umm_al_qura", UmmAlQura)
+	RegisterConvention("gulf", Gulf)
+	RegisterConvention("algerian", Algerian)
+	RegisterConvention("karachi", Karachi)
+	RegisterConvention("diyanet", Diyanet)
+	RegisterConvention("egypt", Egypt)
+	RegisterConvention("egypt_bis", EgyptBis)
+	RegisterConvention("kemenag", Kemenag)
+	RegisterConvention("muis", MUIS)
+	RegisterConvention("jakim", JAKIM)
+	RegisterConvention("uoif", UOIF)
+	RegisterConvention("france15", France15)
+	RegisterConvention("france18", France18)
+	RegisterConvention("tunisia", Tunisia)
+	RegisterConvention("tehran", Tehran)
+	RegisterConvention("jafari", Jafari)
+
+	RegisterAdapter("mecca", Mecca())
+	RegisterAdapter("always_mecca", AlwaysMecca())
+	RegisterAdapter("local_relative_estimation", LocalRelativeEstimation())
+	RegisterAdapter("nearest_day", NearestDay())
+	RegisterAdapter("nearest_latitude", NearestLatitude())
+	RegisterAdapter("nearest_latitude_as_is", NearestLatitudeAsIs())
+	RegisterAdapter("shari_normal_day", ShariNormalDay())
+	RegisterAdapter("angle_based", AngleBased())
+	RegisterAdapter("one_seventh_night", OneSeventhNight())
+	RegisterAdapter("middle_night", MiddleNight())
+	RegisterAdapter("diyanet", DiyanetHighLatitude())
+	RegisterAdapter("fasting_cap_18h", FastingCap(FastingCapConfig{MaxDuration: 18 * time.Hour}))
+	RegisterAdapter("fasting_cap_19h", FastingCap(FastingCapConfig{MaxDuration: 19 * time.Hour}))
+	RegisterAdapter("max_depression", MaxDepression(0))
+	RegisterAdapter("angle_reduction", AngleReduction(AngleReductionConfig{}))
+	RegisterAdapter("aqrab_al_bilad", AqrabAlBilad(AqrabAlBiladConfig{}))
+}
+
+// RegisterConvention registers the constructor of twilight convention with the
+// specified ID. If the ID already registered, it will be replaced.
+func RegisterConvention(id string, fn func() *TwilightConvention) {
+	defaultRegistry.Lock()
+	defer defaultRegistry.Unlock()
+
+	if _, exist := defaultRegistry.conventions[id]; !exist {
+		defaultRegistry.conventionIDs = append(defaultRegistry.conventionIDs, id)
+	}
+	defaultRegistry.conventions[id] = fn
+}
+
+// LookupConvention returns the twilight convention with the specified ID.
+func LookupConvention(id string) (*TwilightConvention, bool) {
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+
+	fn, exist := defaultRegistry.conventions[id]
+	if !exist {
+		return nil, false
+	}
+	return fn(), true
+}
+
+// Conventions returns the IDs of registered twilight conventions, in the order of
+// their registration.
+func Conventions() []string {
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+	return append([]string(nil), defaultRegistry.conventionIDs...)
+}
+
+// RegisterAdapter registers the high latitude adapter with the specified ID. If the
+// ID already registered, it will be replaced. Since functions can't be compared, the
+// adapter is recognized by its function when the config is encoded. Therefore, only
+// adapter that defined as top level function (not closure) can be encoded.
+func RegisterAdapter(id string, adapter HighLatitudeAdapter) {
+	defaultRegistry.Lock()
+	defer defaultRegistry.Unlock()
+
+	if _, exist := defaultRegistry.adapters[id]; !exist {
+		defaultRegistry.adapterIDs = append(defaultRegistry.adapterIDs, id)
+	}
+	defaultRegistry.adapters[id] = adapter
+}
+
+// LookupAdapter returns the high latitude adapter with the specified ID.
+func LookupAdapter(id string) (HighLatitudeAdapter, bool) {
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+
+	adapter, exist := defaultRegistry.adapters[id]
+	return adapter, exist
+}
+
+// Adapters returns the IDs of registered high latitude adapters, in the order of
+// their registration.
+func Adapters() []string {
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+	return append([]string(nil), defaultRegistry.adapterIDs...)
+}
+
+// conventionID returns the registered ID of the convention. The convention is only
+// referred by its ID if its values are still the same as the registered one, e.g.
+// it's not modified after created. If several conventions have the same values (e.g.
+// MWL and Diyanet), the first registered one is used since they are interchangeable.
+func conventionID(tc *TwilightConvention) (string, bool) {
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+
+	for _, id := range defaultRegistry.conventionIDs {
+		if *defaultRegistry.conventions[id]() == *tc {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// adapterID returns the registered ID of the adapter. The adapter is recognized by
+// the name of its function, so closures are never recognized since every closure
+// that created by the same function share the same name.
+func adapterID(adapter HighLatitudeAdapter) (string, bool) {
+	name := funcName(adapter)
+	if name == "" || closureName.MatchString(name) {
+		return "", false
+	}
+
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+
+	for _, id := range defaultRegistry.adapterIDs {
+		if funcName(defaultRegistry.adapters[id]) == name {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// closureName matches the name of anonymous function (e.g. "pkg.Func.func1") and
+// method value (e.g. "pkg.T.Method-fm"), which may capture different values.
+var closureName = regexp.MustCompile(`(\.func\d+(\.\d+)*|-fm)$`)
+
+func funcName(fn any) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	}
+
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return ""
+	}
+	return f.Name()
+}
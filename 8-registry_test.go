@@ -0,0 +1,329 @@
+package prayer_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRegistry(t *testing.T) {
+	// Lookup built-in entries
+	mwl, exist := prayer.LookupConvention("mwl")
+	assertEqual(t, true, exist, "convention mwl should exist")
+	assertEqual(t, *prayer.MWL(), *mwl, "convention mwl has wrong value")
+
+	_, exist = prayer.LookupAdapter("nearest_latitude")
+	assertEqual(t, true, exist, "adapter nearest_latitude should exist")
+
+	// Register custom entries
+	custom := func() *prayer.TwilightConvention {
+		return &prayer.TwilightConvention{FajrAngle: 16.5, IshaAngle: 15.5}
+	}
+	prayer.RegisterConvention("test_custom", custom)
+	cv, exist := prayer.LookupConvention("test_custom")
+	assertEqual(t, true, exist, "custom convention should exist")
+	assertEqual(t, custom().FajrAngle, cv.FajrAngle, "custom convention has wrong fajr angle")
+	assertEqual(t, custom().IshaAngle, cv.IshaAngle, "custom convention has wrong isha angle")
+
+	ids := prayer.Conventions()
+	assertEqual(t, "test_custom", ids[len(ids)-1], "custom convention should be listed last")
+}
+
+func testCustomAdapter(cfg prayer.Config, year int, schedules []prayer.Schedule) []prayer.Schedule {
+	return schedules
+}
+
+func testUnregisteredAdapter(cfg prayer.Config, year int, schedules []prayer.Schedule) []prayer.Schedule {
+	return schedules
+}
+
+func TestConfigJSON(t *testing.T) {
+	prayer.RegisterAdapter("test_custom_adapter", testCustomAdapter)
+
+	// Decode config that use registered IDs
+	var cfg prayer.Config
+	err := json.Unmarshal([]byte(`{
+		"latitude": 51.507222,
+		"longitude": -0.1275,
+		"timezone": "Europe/London",
+		"twilight_convention": "isna",
+		"asr_convention": "hanafi",
+		"high_latitude_adapter": "nearest_latitude",
+		"corrections": {"zuhr": "5m"},
+		"rounding": {"fajr": {"method": "ceil", "granularity": "2m"}},
+		"hijri_calendar": "umm_al_qura",
+		"hijri_offset": -1
+	}`), &cfg)
+	assertNil(t, err, fmt.Sprintf("decode config has error: %v", err))
+	assertEqual(t, "Europe/London", cfg.Timezone.String(), "wrong timezone")
+	assertEqual(t, *prayer.ISNA(), *cfg.TwilightConvention, "wrong twilight convention")
+	assertEqual(t, prayer.Hanafi, cfg.AsrConvention, "wrong asr convention")
+	assertEqual(t, true, cfg.HighLatitudeAdapter != nil, "adapter should exist")
+	assertEqual(t, 5*time.Minute, cfg.Corrections.Zuhr, "wrong zuhr correction")
+	assertEqual(t, prayer.RoundCeil, cfg.Rounding.Fajr.Method, "wrong fajr rounding")
+	assertEqual(t, 2*time.Minute, cfg.Rounding.Fajr.Granularity, "wrong fajr granularity")
+	assertEqual(t, prayer.HijriUmmAlQura, cfg.HijriCalendar, "wrong hijri calendar")
+	assertEqual(t, -1, cfg.HijriOffset, "wrong hijri offset")
+
+	// Unregistered convention is encoded as angles
+	cfg.TwilightConvention = &prayer.TwilightConvention{FajrAngle: 13, IshaAngle: 13}
+	bt, err := json.Marshal(cfg)
+	assertNil(t, err, fmt.Sprintf("encode config has error: %v", err))
+
+	var decoded prayer.Config
+	err = json.Unmarshal(bt, &decoded)
+	assertNil(t, err, fmt.Sprintf("decode config has error: %v", err))
+	assertEqual(t, *cfg.TwilightConvention, *decoded.TwilightConvention, "wrong decoded twilight convention")
+
+	// Registered conventions are encoded using their ID. Conventions with the same
+	// angles are interchangeable, so they use the first registered ID.
+	for id, tc := range map[string]*prayer.TwilightConvention{
+		"isna":         prayer.ISNA(),
+		"umm_al_qura":  prayer.UmmAlQura(),
+		"mwl":          prayer.Diyanet(),
+		"egypt_bis":    prayer.JAKIM(),
+		"astronomical": prayer.Karachi(),
+	} {
+		cfg.TwilightConvention = tc
+		bt, err = json.Marshal(cfg)
+		assertNil(t, err, fmt.Sprintf("encode config with %s has error: %v", id, err))
+
+		var encoded map[string]any
+		json.Unmarshal(bt, &encoded)
+		assertEqual[any](t, id, encoded["twilight_convention"], fmt.Sprintf("convention %s encoded with wrong ID", id))
+
+		json.Unmarshal(bt, &decoded)
+		assertEqual(t, *tc, *decoded.TwilightConvention, fmt.Sprintf("convention %s decoded with wrong value", id))
+	}
+
+	// Modified convention is encoded as angles
+	modified := prayer.MWL()
+	modified.FajrAngle = 19
+	cfg.TwilightConvention = modified
+	bt, _ = json.Marshal(cfg)
+	json.Unmarshal(bt, &decoded)
+	assertEqual(t, *modified, *decoded.TwilightConvention, "modified convention has wrong value")
+
+	var encodedModified map[string]any
+	json.Unmarshal(bt, &encodedModified)
+	_, isID := encodedModified["twilight_convention"].(string)
+	assertEqual(t, false, isID, "modified convention should not be encoded as ID")
+	cfg.TwilightConvention = prayer.ISNA()
+
+	// Registered adapters keep their ID
+	for _, id := range prayer.Adapters() {
+		cfg.HighLatitudeAdapter, _ = prayer.LookupAdapter(id)
+		bt, err = json.Marshal(cfg)
+		assertNil(t, err, fmt.Sprintf("encode config with adapter %s has error: %v", id, err))
+
+		var encoded map[string]any
+		json.Unmarshal(bt, &encoded)
+		assertEqual[any](t, id, encoded["high_latitude_adapter"], fmt.Sprintf("adapter %s encoded with wrong ID", id))
+	}
+
+	// Adapters from the constructors can be encoded and decoded, as long as they are
+	// created with the same parameters as the registered ones
+	for id, adapter := range map[string]prayer.HighLatitudeAdapter{
+		"mecca":                     prayer.Mecca(),
+		"always_mecca":              prayer.AlwaysMecca(),
+		"local_relative_estimation": prayer.LocalRelativeEstimationWith(prayer.LocalRelativeEstimationConfig{}),
+		"nearest_day":               prayer.NearestDay(),
+		"nearest_latitude":          prayer.NearestLatitude(),
+		"nearest_latitude_as_is":    prayer.NearestLatitudeAsIs(),
+		"shari_normal_day":          prayer.ShariNormalDay(),
+		"angle_based":               prayer.AngleBased(),
+		"one_seventh_night":         prayer.OneSeventhNight(),
+		"middle_night":              prayer.MiddleNight(),
+		"diyanet":                   prayer.DiyanetHighLatitude(),
+		"fasting_cap_18h":           prayer.FastingCap(prayer.FastingCapConfig{}),
+		"fasting_cap_19h":           prayer.FastingCap(prayer.FastingCapConfig{MaxDuration: 19 * time.Hour}),
+		"max_depression":            prayer.MaxDepression(0),
+		"angle_reduction":           prayer.AngleReduction(prayer.AngleReductionConfig{Step: 1, MinAngle: 12, SmoothingDays: 15}),
+		"aqrab_al_bilad":            prayer.AqrabAlBilad(prayer.AqrabAlBiladConfig{Step: 0.5}),
+		"test_custom_adapter":       testCustomAdapter,
+	} {
+		cfg.HighLatitudeAdapter = adapter
+		bt, err = json.Marshal(cfg)
+		assertNil(t, err, fmt.Sprintf("encode config with adapter %s has error: %v", id, err))
+
+		var encoded map[string]any
+		json.Unmarshal(bt, &encoded)
+		assertEqual[any](t, id, encoded["high_latitude_adapter"], fmt.Sprintf("adapter %s encoded with wrong ID", id))
+
+		err = json.Unmarshal(bt, &decoded)
+		assertNil(t, err, fmt.Sprintf("decode config with adapter %s has error: %v", id, err))
+		bt2, err := json.Marshal(decoded)
+		assertNil(t, err, fmt.Sprintf("re-encode config with adapter %s has error: %v", id, err))
+		assertEqual(t, string(bt), string(bt2), fmt.Sprintf("adapter %s changed after round trip", id))
+	}
+
+	// Ramadan corrections is kept, so the presets can be encoded
+	preset := prayer.Presets.UmmAlQura(21.42, 39.83, time.UTC)
+	bt, err = json.Marshal(preset)
+	assertNil(t, err, fmt.Sprintf("encode umm al-qura preset has error: %v", err))
+	err = json.Unmarshal(bt, &decoded)
+	assertNil(t, err, fmt.Sprintf("decode umm al-qura preset has error: %v", err))
+	assertEqual(t, preset.RamadanCorrections, decoded.RamadanCorrections, "wrong ramadan corrections")
+
+	// Unregistered adapter and functions can't be encoded
+	for name, invalid := range map[string]prayer.Config{
+		"custom fasting cap":     {HighLatitudeAdapter: prayer.FastingCap(prayer.FastingCapConfig{MaxDuration: 17 * time.Hour})},
+		"custom max depression":  {HighLatitudeAdapter: prayer.MaxDepression(12)},
+		"custom angle reduction": {HighLatitudeAdapter: prayer.AngleReduction(prayer.AngleReductionConfig{MinAngle: 10})},
+		"custom aqrab al-bilad":  {HighLatitudeAdapter: prayer.AqrabAlBilad(prayer.AqrabAlBiladConfig{PerDay: true})},
+		"combined adapter":       {HighLatitudeAdapter: prayer.Chain(prayer.Mecca(), prayer.NearestDay())},
+		"unregistered top level": {HighLatitudeAdapter: testUnregisteredAdapter},
+		"timezone lookup":        {TimezoneLookup: func(float64, float64) (*time.Location, error) { return time.UTC, nil }},
+		"daily corrections":      {DailyCorrections: prayer.MonthlyCorrections{}.Corrections},
+	} {
+		_, err = json.Marshal(invalid)
+		assertEqual(t, true, err != nil, name+" should not be encoded")
+	}
+
+	// Unknown IDs are rejected
+	err = json.Unmarshal([]byte(`{"twilight_convention": "unknown"}`), &decoded)
+	assertEqual(t, true, err != nil, "unknown convention should be rejected")
+}
+
+func TestConfigYAML(t *testing.T) {
+	var cfg prayer.Config
+	err := yaml.Unmarshal([]byte(`
+latitude: 51.507222
+longitude: -0.1275
+timezone: Europe/London
+twilight_convention: isna
+asr_convention: hanafi
+high_latitude_adapter: nearest_latitude
+estimate_missing_asr: true
+corrections:
+  zuhr: 2m
+ramadan_corrections:
+  isha: 30m
+rounding:
+  fajr:
+    method: ceil
+    granularity: 2m
+hijri_calendar: umm_al_qura
+hijri_offset: -1
+`), &cfg)
+	assertNil(t, err, fmt.Sprintf("decode config has error: %v", err))
+	assertEqual(t, "Europe/London", cfg.Timezone.String(), "wrong timezone")
+	assertEqual(t, *prayer.ISNA(), *cfg.TwilightConvention, "wrong twilight convention")
+	assertEqual(t, prayer.Hanafi, cfg.AsrConvention, "wrong asr convention")
+	assertEqual(t, true, cfg.EstimateMissingAsr, "missing asr should be estimated")
+	assertEqual(t, 2*time.Minute, cfg.Corrections.Zuhr, "wrong zuhr correction")
+	assertEqual(t, 30*time.Minute, cfg.RamadanCorrections.Isha, "wrong isha ramadan correction")
+	assertEqual(t, prayer.RoundCeil, cfg.Rounding.Fajr.Method, "wrong fajr rounding")
+	assertEqual(t, 2*time.Minute, cfg.Rounding.Fajr.Granularity, "wrong fajr granularity")
+	assertEqual(t, prayer.HijriUmmAlQura, cfg.HijriCalendar, "wrong hijri calendar")
+	assertEqual(t, -1, cfg.HijriOffset, "wrong hijri offset")
+
+	bt, err := yaml.Marshal(cfg)
+	assertNil(t, err, fmt.Sprintf("encode config has error: %v", err))
+
+	var encoded map[string]any
+	err = yaml.Unmarshal(bt, &encoded)
+	assertNil(t, err, fmt.Sprintf("decode encoded config has error: %v", err))
+	assertEqual[any](t, "isna", encoded["twilight_convention"], "convention encoded with wrong ID")
+	assertEqual[any](t, "nearest_latitude", encoded["high_latitude_adapter"], "adapter encoded with wrong ID")
+
+	// Unregistered convention is encoded as angles
+	cfg.TwilightConvention = &prayer.TwilightConvention{FajrAngle: 13, IshaAngle: 13, MaghribDuration: time.Hour}
+	bt, err = yaml.Marshal(cfg)
+	assertNil(t, err, fmt.Sprintf("encode config has error: %v", err))
+
+	var decoded prayer.Config
+	err = yaml.Unmarshal(bt, &decoded)
+	assertNil(t, err, fmt.Sprintf("decode config has error: %v", err))
+	assertEqual(t, *cfg.TwilightConvention, *decoded.TwilightConvention, "wrong decoded twilight convention")
+	assertEqual(t, cfg.Corrections, decoded.Corrections, "wrong decoded corrections")
+	assertEqual(t, *cfg.Rounding, *decoded.Rounding, "wrong decoded rounding")
+	assertEqual(t, true, decoded.EstimateMissingAsr, "wrong decoded missing asr option")
+
+	// Unregistered adapter can't be encoded
+	cfg.HighLatitudeAdapter = prayer.Chain(prayer.Mecca(), prayer.NearestDay())
+	_, err = yaml.Marshal(cfg)
+	assertEqual(t, true, err != nil, "unregistered adapter should not be encoded")
+
+	// Unknown IDs are rejected
+	err = yaml.Unmarshal([]byte("twilight_convention: unknown"), &decoded)
+	assertEqual(t, true, err != nil, "unknown convention should be rejected")
+}
+
+func TestMetadata(t *testing.T) {
+	info, _ := prayer.LookupConventionInfo("mwl")
+	assertEqual(t, prayer.MWL().MaxLatitude(), info.MaxLatitude, "mwl has wrong max latitude")
+	assertEqual(t, false, info.RequiresSunriseSunset, "mwl should not require sunrise and sunset")
+
+	info, _ = prayer.LookupConventionInfo("umm_al_qura")
+	assertEqual(t, true, info.RequiresSunriseSunset, "umm al-qura should require sunrise and sunset")
+
+	for _, id := range prayer.Conventions() {
+		if id == "test_custom" {
+			continue
+		}
+
+		info, exist := prayer.LookupConventionInfo(id)
+		assertEqual(t, true, exist, fmt.Sprintf("convention %s has no metadata", id))
+		assertEqual(t, true, info.Name != "" && info.Description != "", fmt.Sprintf("convention %s has empty metadata", id))
+		assertEqual(t, true, info.MaxLatitude > info.MinLatitude, fmt.Sprintf("convention %s has invalid latitude range", id))
+	}
+
+	for _, id := range prayer.Adapters() {
+		if id == "test_custom_adapter" {
+			continue
+		}
+
+		info, exist := prayer.LookupAdapterInfo(id)
+		assertEqual(t, true, exist, fmt.Sprintf("adapter %s has no metadata", id))
+		assertEqual(t, true, info.Name != "" && info.Description != "", fmt.Sprintf("adapter %s has empty metadata", id))
+		assertEqual(t, true, info.MaxLatitude > info.MinLatitude, fmt.Sprintf("adapter %s has invalid latitude range", id))
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	testRecommend(t, "Jakarta", -6.175, 106.825, "", "kemenag", prayer.Shafii, "")
+	testRecommend(t, "Karachi", 24.86, 67.01, "PK", "karachi", prayer.Hanafi, "")
+	testRecommend(t, "London", 51.507222, -0.1275, "GB", "mwl", prayer.Shafii, "nearest_latitude")
+	testRecommend(t, "Tromso", 69.682778, 18.942778, "NO", "mwl", prayer.Shafii, "mecca")
+	testRecommend(t, "Lahore", 31.55, 74.34, "", "karachi", prayer.Hanafi, "")
+	testRecommend(t, "Colombo", 6.93, 79.85, "", "mwl", prayer.Shafii, "")
+	testRecommend(t, "Dubai", 25.2, 55.27, "", "gulf", prayer.Shafii, "")
+	testRecommend(t, "Dubai", 25.2, 55.27, "AE", "gulf", prayer.Shafii, "")
+	testRecommend(t, "Riyadh", 24.71, 46.68, "", "umm_al_qura", prayer.Shafii, "")
+	testRecommend(t, "Dammam", 26.43, 50.1, "", "umm_al_qura", prayer.Shafii, "")
+	testRecommend(t, "Muscat", 23.59, 58.41, "", "gulf", prayer.Shafii, "")
+	testRecommend(t, "Bushehr", 28.97, 50.84, "", "tehran", prayer.Shafii, "")
+	testRecommend(t, "Geneva", 46.2, 6.15, "CH", "mwl", prayer.Shafii, "nearest_latitude")
+	testRecommend(t, "Istanbul", 41.01, 28.98, "", "diyanet", prayer.Shafii, "")
+	testRecommend(t, "Delhi", 28.61, 77.21, "", "karachi", prayer.Hanafi, "")
+	testRecommend(t, "Dhaka", 23.81, 90.41, "", "karachi", prayer.Hanafi, "")
+	testRecommend(t, "Chennai", 13.08, 80.27, "", "mwl", prayer.Shafii, "")
+	testRecommend(t, "Chennai", 13.08, 80.27, "IN", "karachi", prayer.Hanafi, "")
+	testRecommend(t, "New York", 40.71, -74.01, "", "isna", prayer.Shafii, "")
+	testRecommend(t, "Houston", 29.76, -95.37, "", "isna", prayer.Shafii, "")
+	testRecommend(t, "Miami", 25.76, -80.19, "", "isna", prayer.Shafii, "")
+	testRecommend(t, "Austin", 30.27, -97.74, "", "isna", prayer.Shafii, "")
+	testRecommend(t, "Toronto", 43.65, -79.38, "", "isna", prayer.Shafii, "")
+	testRecommend(t, "Edmonton", 53.55, -113.49, "", "isna", prayer.Shafii, "nearest_latitude")
+	testRecommend(t, "Anchorage", 61.22, -149.9, "", "isna", prayer.Shafii, "nearest_latitude")
+	testRecommend(t, "Mexico City", 19.43, -99.13, "", "mwl", prayer.Shafii, "")
+	testRecommend(t, "Monterrey", 25.67, -100.31, "", "mwl", prayer.Shafii, "")
+	testRecommend(t, "Monterrey", 25.67, -100.31, "MX", "mwl", prayer.Shafii, "")
+	testRecommend(t, "Matamoros", 25.87, -97.5, "", "mwl", prayer.Shafii, "")
+	testRecommend(t, "Havana", 23.11, -82.37, "", "mwl", prayer.Shafii, "")
+	testRecommend(t, "Nassau", 25.04, -77.35, "", "mwl", prayer.Shafii, "")
+}
+
+func testRecommend(t *testing.T, name string, latitude, longitude float64, country string,
+	conventionID string, asr prayer.AsrConvention, adapterID string) {
+	r := prayer.Recommend(latitude, longitude, country)
+	msgFormat := "recommendation for %s: want %v got %v"
+	assertEqual(t, conventionID, r.ConventionID, fmt.Sprintf(msgFormat, name, conventionID, r.ConventionID))
+	assertEqual(t, asr, r.AsrConvention, fmt.Sprintf(msgFormat, name, asr, r.AsrConvention))
+	assertEqual(t, adapterID, r.AdapterID, fmt.Sprintf(msgFormat, name, adapterID, r.AdapterID))
+}
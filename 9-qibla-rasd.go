@@ -0,0 +1,209 @@
+package prayer
+
+import (
+	"math"
+	"time"
+
+	"github.com/hablullah/go-sampa"
+)
+
+// QiblaShadow is the times in a day when the Sun can be used to check the Qibla
+// direction (Rasd al-Qibla). At those times, the shadow of a vertical object lies on
+// the line of the Qibla.
+type QiblaShadow struct {
+	// Date is the ISO date, useful for logging.
+	Date string
+
+	// TowardQibla is the times when the Sun is in the direction of the Qibla, so the
+	// shadow of a vertical object points away from the Qibla. Usually it occurs at
+	// most once a day, but it might occur twice in the tropics when the Sun passes
+	// north (or south) of the zenith.
+	TowardQibla []time.Time
+
+	// AwayFromQibla is the times when the Sun is in the opposite direction of the
+	// Qibla, so the shadow of a vertical object points toward the Qibla.
+	AwayFromQibla []time.Time
+}
+
+// CalculateQiblaShadows calculates the Qibla shadow times for the entire year, using
+// the location and time zone in the configuration. The times are only reported while
+// the Sun is above the horizon, and they are precise to seconds regardless of the
+// rounding in configuration. The Qibla direction is the great circle bearing from
+// `CalculateQibla` using spherical model.
+func CalculateQiblaShadows(cfg Config, year int) ([]QiblaShadow, error) {
+	tz, err := cfg.location()
+	if err != nil {
+		return nil, err
+	}
+
+	qibla, err := CalculateQibla(cfg.Latitude, cfg.Longitude, QiblaSpherical)
+	if err != nil {
+		return nil, err
+	}
+
+	toward := qibla.Bearing
+	away := normalizeBearing(qibla.Bearing + 180)
+
+	location := sampa.Location{
+		Latitude:  cfg.Latitude,
+		Longitude: cfg.Longitude,
+		Elevation: cfg.Elevation,
+	}
+
+	// At the Kaaba itself there are no Qibla direction
+	atKaaba := qibla.Distance == 0
+
+	var shadows []QiblaShadow
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, tz)
+	for dt := start; dt.Year() == year; dt = dt.AddDate(0, 0, 1) {
+		shadow := QiblaShadow{Date: dt.Format("2006-01-02")}
+		if atKaaba {
+			shadows = append(shadows, shadow)
+			continue
+		}
+
+		// Only look for the shadow while the Sun is up
+		events, err := sampa.GetSunEvents(dt, location, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		dayStart, dayEnd := events.Sunrise.DateTime, events.Sunset.DateTime
+		if dayStart.IsZero() || dayEnd.IsZero() {
+			if events.Transit.TopocentricElevationAngle <= 0 {
+				shadows = append(shadows, shadow)
+				continue
+			}
+			dayStart, dayEnd = dt, dt.AddDate(0, 0, 1)
+		}
+
+		shadow.TowardQibla, err = findSunAzimuth(location, dayStart, dayEnd, toward)
+		if err != nil {
+			return nil, err
+		}
+
+		shadow.AwayFromQibla, err = findSunAzimuth(location, dayStart, dayEnd, away)
+		if err != nil {
+			return nil, err
+		}
+
+		shadows = append(shadows, shadow)
+	}
+
+	return shadows, nil
+}
+
+// KaabaZenithPassages returns the times in the specified year when the Sun passes
+// over the zenith of the Kaaba, i.e. at noon in Mecca on the two days when the
+// declination of the Sun equals the latitude of the Kaaba (around 27-28 May and
+// 15-16 July). At those times the shadow of every vertical object in the daylit
+// hemisphere points away from the Qibla, so it can be checked anywhere without any
+// calculation. If tz is nil, the times will be in UTC.
+func KaabaZenithPassages(year int, tz *time.Location) ([]time.Time, error) {
+	if tz == nil {
+		tz = time.UTC
+	}
+
+	meccaTz := meccaTimezone()
+	kaaba := sampa.Location{
+		Latitude:  kaabaLatitude,
+		Longitude: kaabaLongitude,
+	}
+
+	// Look for the days when the difference between the declination at noon and the
+	// latitude of the Kaaba changes sign, then pick the one nearest to zero.
+	var passages []time.Time
+	var prevTransit sampa.SunPosition
+	var prevDiff float64
+
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, meccaTz)
+	for dt := start; dt.Year() == year; dt = dt.AddDate(0, 0, 1) {
+		events, err := sampa.GetSunEvents(dt, kaaba, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		transit := events.Transit
+		diff := transit.TopocentricDeclination - kaabaLatitude
+		if !prevTransit.IsZero() && (diff >= 0) != (prevDiff >= 0) {
+			nearest := transit
+			if math.Abs(prevDiff) < math.Abs(diff) {
+				nearest = prevTransit
+			}
+			passages = append(passages, nearest.DateTime.In(tz))
+		}
+
+		prevTransit, prevDiff = transit, diff
+	}
+
+	return passages, nil
+}
+
+// findSunAzimuth returns the times between start and end when the Sun's azimuth
+// equals the target azimuth.
+func findSunAzimuth(location sampa.Location, start, end time.Time, target float64) ([]time.Time, error) {
+	const step = 10 * time.Minute
+
+	// azimuthDiff returns the difference between the Sun's azimuth and the target,
+	// normalized into (-180, 180].
+	azimuthDiff := func(t time.Time) (float64, error) {
+		pos, err := sampa.GetSunPosition(t, location, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		diff := math.Mod(pos.TopocentricAzimuthAngle-target+540, 360) - 180
+		return diff, nil
+	}
+
+	var times []time.Time
+	prevTime := start
+	prevDiff, err := azimuthDiff(start)
+	if err != nil {
+		return nil, err
+	}
+
+	for prevTime.Before(end) {
+		currTime := prevTime.Add(step)
+		if currTime.After(end) {
+			currTime = end
+		}
+
+		currDiff, err := azimuthDiff(currTime)
+		if err != nil {
+			return nil, err
+		}
+
+		// The sign also changes when the azimuth crosses the opposite of the target,
+		// which is excluded by checking the size of the jump.
+		if (prevDiff < 0) != (currDiff < 0) && math.Abs(currDiff-prevDiff) < 180 {
+			t, err := bisectSunAzimuth(azimuthDiff, prevTime, currTime, prevDiff)
+			if err != nil {
+				return nil, err
+			}
+			times = append(times, t)
+		}
+
+		prevTime, prevDiff = currTime, currDiff
+	}
+
+	return times, nil
+}
+
+func bisectSunAzimuth(azimuthDiff func(time.Time) (float64, error), lo, hi time.Time, loDiff float64) (time.Time, error) {
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		midDiff, err := azimuthDiff(mid)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if (midDiff < 0) == (loDiff < 0) {
+			lo, loDiff = mid, midDiff
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo.Round(time.Second), nil
+}
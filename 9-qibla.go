@@ -0,0 +1,220 @@
+package prayer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Coordinate of the Kaaba in Mecca.
+const (
+	kaabaLatitude  = 21.425506007708996
+	kaabaLongitude = 39.8254579358597
+)
+
+// Earth model that used for calculating the Qibla.
+const (
+	// earthMeanRadius is the mean radius of Earth in km, used by spherical model.
+	earthMeanRadius = 6371.0088
+
+	// wgs84SemiMajorAxis is the equatorial radius of WGS-84 ellipsoid in km.
+	wgs84SemiMajorAxis = 6378.137
+
+	// wgs84Flattening is the flattening of WGS-84 ellipsoid.
+	wgs84Flattening = 1 / 298.257223563
+)
+
+// QiblaMethod is the Earth model that used for calculating the Qibla.
+type QiblaMethod int
+
+const (
+	// QiblaSpherical treats Earth as a sphere. It's the method used by most of
+	// prayer apps, with error up to around 0.2 degrees for the bearing and 0.5% for
+	// the distance.
+	QiblaSpherical QiblaMethod = iota
+
+	// QiblaEllipsoidal treats Earth as WGS-84 ellipsoid and solves the geodesic
+	// using Vincenty's inverse formula, which accurate to sub-arcminute. For location
+	// that nearly antipodal to the Kaaba the formula doesn't converge, so it will
+	// fall back to the spherical model and return `ErrQiblaApproximated`.
+	QiblaEllipsoidal
+)
+
+// ErrQiblaApproximated is returned by `CalculateQibla` along with the result of the
+// spherical model, when the ellipsoidal model can't be solved for the location.
+var ErrQiblaApproximated = errors.New("qibla is approximated using spherical model")
+
+// String returns the name of the Qibla method.
+func (m QiblaMethod) String() string {
+	switch m {
+	case QiblaEllipsoidal:
+		return "ellipsoidal"
+	default:
+		return "spherical"
+	}
+}
+
+// Qibla is the direction and distance from a location to the Kaaba.
+type Qibla struct {
+	// Bearing is the initial bearing of the shortest path (great circle or geodesic)
+	// to the Kaaba, in degrees clockwise from the true north.
+	Bearing float64
+
+	// Distance is the distance of the shortest path to the Kaaba, in km.
+	Distance float64
+
+	// RhumbBearing is the constant bearing of the rhumb line (loxodrome) to the
+	// Kaaba, in degrees clockwise from the true north. It's not the direction of
+	// Qibla, but some people use it as comparison.
+	RhumbBearing float64
+
+	// RhumbDistance is the distance along the rhumb line to the Kaaba, in km.
+	RhumbDistance float64
+}
+
+// CalculateQibla calculates the Qibla direction and distance for the location with
+// specified latitude and longitude, the same as used in `Config`. The rhumb line is
+// always calculated using spherical model. It will return error if the coordinate is
+// invalid, or `ErrQiblaApproximated` along with the result if the ellipsoidal model
+// falls back to the spherical model.
+func CalculateQibla(latitude, longitude float64, method QiblaMethod) (Qibla, error) {
+	if math.IsNaN(latitude) || latitude < -90 || latitude > 90 {
+		return Qibla{}, fmt.Errorf("invalid latitude %v", latitude)
+	}
+
+	if math.IsNaN(longitude) || longitude < -180 || longitude > 180 {
+		return Qibla{}, fmt.Errorf("invalid longitude %v", longitude)
+	}
+
+	var q Qibla
+	var err error
+	switch method {
+	case QiblaEllipsoidal:
+		bearing, distance, converged := vincentyInverse(latitude, longitude, kaabaLatitude, kaabaLongitude)
+		if !converged {
+			// Near the antipode every directions are almost equally short, so the
+			// great circle is good enough
+			bearing, distance = greatCircle(latitude, longitude, kaabaLatitude, kaabaLongitude)
+			err = ErrQiblaApproximated
+		}
+		q.Bearing, q.Distance = bearing, distance
+	default:
+		q.Bearing, q.Distance = greatCircle(latitude, longitude, kaabaLatitude, kaabaLongitude)
+	}
+
+	q.RhumbBearing, q.RhumbDistance = rhumbLine(latitude, longitude, kaabaLatitude, kaabaLongitude)
+	return q, err
+}
+
+// greatCircle returns the initial bearing (in degrees) and distance (in km) of the
+// great circle between two coordinates.
+func greatCircle(lat1, lon1, lat2, lon2 float64) (bearing, distance float64) {
+	phi1, phi2 := degToRad(lat1), degToRad(lat2)
+	dLambda := degToRad(lon2 - lon1)
+
+	y := math.Sin(dLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLambda)
+	bearing = normalizeBearing(radToDeg(math.Atan2(y, x)))
+
+	// Use haversine for the distance since it's stable for small distance
+	sinDPhi := math.Sin((phi2 - phi1) / 2)
+	sinDLambda := math.Sin(dLambda / 2)
+	a := sinDPhi*sinDPhi + math.Cos(phi1)*math.Cos(phi2)*sinDLambda*sinDLambda
+	distance = 2 * earthMeanRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+	return
+}
+
+// rhumbLine returns the bearing (in degrees) and distance (in km) of the rhumb line
+// between two coordinates.
+func rhumbLine(lat1, lon1, lat2, lon2 float64) (bearing, distance float64) {
+	phi1, phi2 := degToRad(lat1), degToRad(lat2)
+	dPhi := phi2 - phi1
+
+	// Take the shorter way around the globe
+	dLambda := degToRad(lon2 - lon1)
+	if math.Abs(dLambda) > math.Pi {
+		if dLambda > 0 {
+			dLambda -= 2 * math.Pi
+		} else {
+			dLambda += 2 * math.Pi
+		}
+	}
+
+	// Projected latitude difference, with ratio q to handle E-W line where dPsi is 0
+	dPsi := math.Log(math.Tan(math.Pi/4+phi2/2) / math.Tan(math.Pi/4+phi1/2))
+	q := math.Cos(phi1)
+	if math.Abs(dPsi) > 1e-12 {
+		q = dPhi / dPsi
+	}
+
+	bearing = normalizeBearing(radToDeg(math.Atan2(dLambda, dPsi)))
+	distance = math.Sqrt(dPhi*dPhi+q*q*dLambda*dLambda) * earthMeanRadius
+	return
+}
+
+// vincentyInverse returns the initial bearing (in degrees) and distance (in km) of
+// the geodesic between two coordinates on WGS-84 ellipsoid. It returns false if the
+// formula doesn't converge, which happens for nearly antipodal coordinates.
+func vincentyInverse(lat1, lon1, lat2, lon2 float64) (bearing, distance float64, converged bool) {
+	const a = wgs84SemiMajorAxis
+	const f = wgs84Flattening
+	const b = a * (1 - f)
+
+	L := degToRad(lon2 - lon1)
+	U1 := math.Atan((1 - f) * math.Tan(degToRad(lat1)))
+	U2 := math.Atan((1 - f) * math.Tan(degToRad(lat2)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	var sinLambda, cosLambda, sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+	for i := 0; i < 200; i++ {
+		sinLambda, cosLambda = math.Sincos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			// Coincident points
+			return 0, 0, true
+		}
+
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+
+		// On equatorial line cosSqAlpha is 0
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+
+		C := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		prevLambda := lambda
+		lambda = L + (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prevLambda) < 1e-12 {
+			converged = true
+			break
+		}
+	}
+
+	if !converged {
+		return 0, 0, false
+	}
+
+	uSq := cosSqAlpha * (a*a - b*b) / (b * b)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	dSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+	distance = b * A * (sigma - dSigma)
+	bearing = normalizeBearing(radToDeg(math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)))
+	return bearing, distance, true
+}
+
+func normalizeBearing(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
@@ -0,0 +1,118 @@
+package prayer_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hablullah/go-prayer"
+)
+
+func TestCalculateQibla(t *testing.T) {
+	// Expected bearings are the common published values, which use spherical model
+	testQibla(t, "Jakarta", -6.175, 106.825, 295.15, 7920)
+	testQibla(t, "London", 51.507222, -0.1275, 118.99, 4790)
+	testQibla(t, "New York", 40.7128, -74.006, 58.48, 10300)
+	testQibla(t, "Wellington", -41.288889, 174.777222, 256.39, 15270)
+
+	// Rhumb line to the Kaaba from the same latitude is due east
+	q, err := prayer.CalculateQibla(21.425506007708996, 0, prayer.QiblaSpherical)
+	assertNil(t, err, fmt.Sprintf("qibla has error: %v", err))
+	assertEqual(t, true, math.Abs(q.RhumbBearing-90) < 1e-9, fmt.Sprintf("wrong rhumb bearing: %v", q.RhumbBearing))
+
+	// Nearly antipodal location falls back to the spherical model
+	for _, lon := range []float64{-140.1745420641403, -140.2, -139.9} {
+		ellipsoidal, err := prayer.CalculateQibla(-21.425506007708996, lon, prayer.QiblaEllipsoidal)
+		assertEqual(t, prayer.ErrQiblaApproximated, err, fmt.Sprintf("antipodal qibla in %v should be approximated", lon))
+		spherical, _ := prayer.CalculateQibla(-21.425506007708996, lon, prayer.QiblaSpherical)
+		msg := fmt.Sprintf("antipodal qibla in %v => want %v got %v", lon, spherical, ellipsoidal)
+		assertEqual(t, true, math.Abs(ellipsoidal.Distance-spherical.Distance)/spherical.Distance < 0.006, msg)
+		assertEqual(t, false, math.IsNaN(ellipsoidal.Bearing), msg)
+	}
+
+	// Invalid coordinates are rejected
+	for _, coord := range [][2]float64{
+		{math.NaN(), 0}, {0, math.NaN()},
+		{math.Inf(1), 0}, {0, math.Inf(-1)},
+		{90.5, 0}, {-91, 0}, {0, 180.5}, {0, -181},
+	} {
+		_, err = prayer.CalculateQibla(coord[0], coord[1], prayer.QiblaSpherical)
+		assertEqual(t, true, err != nil, fmt.Sprintf("qibla in %v should be rejected", coord))
+	}
+}
+
+func testQibla(t *testing.T, name string, latitude, longitude, bearing, distance float64) {
+	spherical, err := prayer.CalculateQibla(latitude, longitude, prayer.QiblaSpherical)
+	assertNil(t, err, fmt.Sprintf("qibla in %s has error: %v", name, err))
+
+	ellipsoidal, err := prayer.CalculateQibla(latitude, longitude, prayer.QiblaEllipsoidal)
+	assertNil(t, err, fmt.Sprintf("ellipsoidal qibla in %s has error: %v", name, err))
+
+	msgFormat := "%s, %s => want %v got %v"
+	assertEqual(t, true, math.Abs(spherical.Bearing-bearing) < 0.02,
+		fmt.Sprintf(msgFormat, name, "bearing", bearing, spherical.Bearing))
+	assertEqual(t, true, math.Abs(spherical.Distance-distance)/distance < 0.01,
+		fmt.Sprintf(msgFormat, name, "distance", distance, spherical.Distance))
+
+	// Ellipsoidal model only differs slightly from the spherical model
+	assertEqual(t, true, math.Abs(ellipsoidal.Bearing-spherical.Bearing) < 0.3,
+		fmt.Sprintf(msgFormat, name, "ellipsoidal bearing", spherical.Bearing, ellipsoidal.Bearing))
+	assertEqual(t, true, math.Abs(ellipsoidal.Distance-spherical.Distance)/distance < 0.006,
+		fmt.Sprintf(msgFormat, name, "ellipsoidal distance", spherical.Distance, ellipsoidal.Distance))
+}
+
+func TestKaabaZenithPassages(t *testing.T) {
+	meccaTz, _ := time.LoadLocation("Asia/Riyadh")
+	passages, err := prayer.KaabaZenithPassages(2023, meccaTz)
+	assertNil(t, err, fmt.Sprintf("zenith passages has error: %v", err))
+	assertEqual(t, 2, len(passages), "there should be two zenith passages")
+
+	// Commonly published times are 28 May at 12:18 and 16 July at 12:27
+	expected := []time.Time{
+		time.Date(2023, 5, 28, 12, 18, 0, 0, meccaTz),
+		time.Date(2023, 7, 16, 12, 27, 0, 0, meccaTz),
+	}
+
+	for i, e := range expected {
+		diff := passages[i].Sub(e).Abs()
+		assertEqual(t, true, diff < time.Minute, fmt.Sprintf("zenith passage: want %v got %v", e, passages[i]))
+	}
+}
+
+func TestCalculateQiblaShadows(t *testing.T) {
+	asiaJakarta, _ := time.LoadLocation("Asia/Jakarta")
+	shadows, err := prayer.CalculateQiblaShadows(prayer.Config{
+		Latitude:  -6.175,
+		Longitude: 106.825,
+		Timezone:  asiaJakarta,
+	}, 2023)
+	assertNil(t, err, fmt.Sprintf("qibla shadows has error: %v", err))
+	assertEqual(t, 365, len(shadows), "wrong number of days")
+
+	// When the Sun is over the Kaaba, the Sun is in Qibla direction everywhere
+	s := shadows[146]
+	assertEqual(t, "2023-05-27", s.Date, "wrong date")
+	assertEqual(t, 1, len(s.TowardQibla), "sun should be in qibla direction once")
+	expected := time.Date(2023, 5, 27, 16, 17, 0, 0, asiaJakarta)
+	diff := s.TowardQibla[0].Sub(expected).Abs()
+	assertEqual(t, true, diff < 3*time.Minute, fmt.Sprintf("qibla shadow: want %v got %v", expected, s.TowardQibla[0]))
+
+	// Every reported times must be while the Sun is up
+	for _, s := range shadows {
+		for _, t2 := range append(s.TowardQibla, s.AwayFromQibla...) {
+			hour := t2.Hour()
+			assertEqual(t, true, hour >= 5 && hour <= 18, fmt.Sprintf("qibla shadow at night: %v", t2))
+		}
+	}
+
+	// In polar night there are no shadow
+	europeOslo, _ := time.LoadLocation("Europe/Oslo")
+	shadows, err = prayer.CalculateQiblaShadows(prayer.Config{
+		Latitude:  69.682778,
+		Longitude: 18.942778,
+		Timezone:  europeOslo,
+	}, 2023)
+	assertNil(t, err, fmt.Sprintf("qibla shadows has error: %v", err))
+	assertEqual(t, 0, len(shadows[0].TowardQibla)+len(shadows[0].AwayFromQibla), "no shadow in polar night")
+}
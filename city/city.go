@@ -0,0 +1,344 @@
+// Package city provides offline search for cities, so the prayer times can be
+// calculated from a city name instead of coordinates.
+//
+// The cities are embedded from a list derived from GeoNames (https://www.geonames.org)
+// which contains the cities with large population, the cities with significant
+// Muslim population and the cities in high latitude. The list can be regenerated from
+// the GeoNames dump using `scripts/city-gen`, where the additional cities are listed
+// in `scripts/city-gen/include.txt`.
+//
+// This package also imports "time/tzdata", so the time zones can be loaded even in
+// system without "/usr/share/zoneinfo", e.g. in minimal container.
+package city
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata"
+	"unicode"
+
+	"github.com/hablullah/go-prayer"
+)
+
+//go:embed cities.tsv
+var citiesTSV string
+
+// City is a city with its location data.
+type City struct {
+	// Name is the name of the city, might contain non ASCII characters.
+	Name string
+
+	// ASCIIName is the name of the city in plain ASCII characters.
+	ASCIIName string
+
+	// AlternateNames is the other names of the city, e.g. the local name, the name
+	// in other languages or the historical name.
+	AlternateNames []string
+
+	// CountryCode is the ISO 3166-1 alpha-2 code of the country.
+	CountryCode string
+
+	// Latitude is the latitude of the city.
+	Latitude float64
+
+	// Longitude is the longitude of the city.
+	Longitude float64
+
+	// Elevation is the elevation of the city above sea level, in meters.
+	Elevation float64
+
+	// Population is the population of the city.
+	Population int
+
+	// Timezone is the IANA name of the city's time zone.
+	Timezone string
+}
+
+// Location returns the time zone of the city.
+func (c City) Location() (*time.Location, error) {
+	return time.LoadLocation(c.Timezone)
+}
+
+// Config returns the prayer config with the location of the city. The conventions
+// are left empty, so they can be specified later, e.g. using `prayer.Recommend`.
+func (c City) Config() (prayer.Config, error) {
+	tz, err := c.Location()
+	if err != nil {
+		return prayer.Config{}, err
+	}
+
+	return prayer.Config{
+		Latitude:  c.Latitude,
+		Longitude: c.Longitude,
+		Elevation: c.Elevation,
+		Timezone:  tz,
+	}, nil
+}
+
+type indexedCity struct {
+	City
+	keys []string
+}
+
+var (
+	loadOnce sync.Once
+	cities   []indexedCity
+)
+
+// All returns every embedded cities.
+func All() []City {
+	loadOnce.Do(loadCities)
+
+	result := make([]City, len(cities))
+	for i, c := range cities {
+		result[i] = c.City
+	}
+	return result
+}
+
+// Lookup returns the city whose name or alternate name is exactly the specified
+// name, ignoring case, diacritics and punctuation. If there are several cities with
+// the same name, the one with largest population is returned. The name can be
+// followed by the country code to choose between them, e.g. "Hyderabad, PK".
+func Lookup(name string) (City, bool) {
+	matches := search(name, 1, true)
+	if len(matches) == 0 {
+		return City{}, false
+	}
+	return matches[0], true
+}
+
+// Search returns at most `limit` cities that match the query, sorted by relevance.
+// The query is matched against the name and alternate names of the cities, ignoring
+// case, diacritics and punctuation. Beside the exact match, the query also matches
+// prefix (e.g. "Kuala"), substring and typo (e.g. "Jakrta"). Like `Lookup`, the query
+// can be followed by the country code. If limit is not positive, all matches will be
+// returned.
+func Search(query string, limit int) []City {
+	return search(query, limit, false)
+}
+
+type match struct {
+	City
+	score int
+}
+
+func search(query string, limit int, exactOnly bool) []City {
+	loadOnce.Do(loadCities)
+
+	// Extract country code from query
+	var countryCode string
+	if idx := strings.LastIndex(query, ","); idx >= 0 {
+		code := strings.TrimSpace(query[idx+1:])
+		if len(code) == 2 {
+			countryCode = strings.ToUpper(code)
+			query = query[:idx]
+		}
+	}
+
+	q := normalize(query)
+	if q == "" {
+		return nil
+	}
+
+	// Score each city
+	var matches []match
+	for _, c := range cities {
+		if countryCode != "" && c.CountryCode != countryCode {
+			continue
+		}
+
+		score := -1
+		for _, key := range c.keys {
+			s := matchScore(q, key)
+			if s >= 0 && (score < 0 || s < score) {
+				score = s
+			}
+		}
+
+		if score < 0 || (exactOnly && score > 0) {
+			continue
+		}
+
+		matches = append(matches, match{City: c.City, score: score})
+	}
+
+	// Sort by score, then by population
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return matches[i].Population > matches[j].Population
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	result := make([]City, len(matches))
+	for i, m := range matches {
+		result[i] = m.City
+	}
+	return result
+}
+
+// matchScore returns the score of how well the query matches the key, where lower
+// score is better. It returns -1 if the query doesn't match.
+func matchScore(query, key string) int {
+	switch {
+	case query == key:
+		return 0
+	case strings.HasPrefix(key, query):
+		return 1
+	case strings.Contains(key, " "+query):
+		return 2
+	case strings.Contains(key, query):
+		return 3
+	}
+
+	// Allow one typo for every three characters
+	maxDistance := len(query) / 3
+	if maxDistance == 0 {
+		return -1
+	}
+
+	if distance := levenshtein(query, key); distance <= maxDistance {
+		return 3 + distance
+	}
+	return -1
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
+
+// diacritics maps the non ASCII letters into their ASCII form.
+var diacritics = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a",
+	'æ': "ae", 'ç': "c", 'č': "c", 'ć': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i",
+	'ð': "d", 'đ': "d", 'ġ': "g", 'ğ': "g", 'ł': "l", 'ñ': "n", 'ń': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe", 'ř': "r", 'ş': "s", 'š': "s", 'ś': "s", 'ș': "s", 'ß': "ss",
+	'ţ': "t", 'ț': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u",
+	'ý': "y", 'ÿ': "y", 'ž': "z", 'ź': "z", 'ż': "z",
+	'ạ': "a", 'ả': "a", 'ầ': "a", 'ộ': "o", 'ơ': "o", 'ư': "u",
+}
+
+// normalize converts the name into lower case ASCII, where punctuations are
+// replaced by space and consecutive spaces are merged.
+func normalize(name string) string {
+	var sb strings.Builder
+	lastSpace := true
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			sb.WriteRune(r)
+			lastSpace = false
+		case diacritics[r] != "":
+			sb.WriteString(diacritics[r])
+			lastSpace = false
+		case r == '\'' || r == '’' || r == 'ʼ' || unicode.Is(unicode.Mn, r):
+			// Apostrophe and combining mark are removed, e.g. "N'Djamena" => "ndjamena"
+		case unicode.IsLetter(r):
+			sb.WriteRune(r)
+			lastSpace = false
+		default:
+			if !lastSpace {
+				sb.WriteByte(' ')
+				lastSpace = true
+			}
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// loadCities parses the embedded cities. Since the data is embedded at compile
+// time, invalid data is a programming error so it will panic.
+func loadCities() {
+	for i, line := range strings.Split(citiesTSV, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.Split(line, "\t")
+		if len(parts) != 9 {
+			panic(fmt.Sprintf("city: invalid city at line %d", i+1))
+		}
+
+		var alternateNames []string
+		if parts[2] != "" {
+			alternateNames = strings.Split(parts[2], ",")
+		}
+
+		c := City{
+			Name:           parts[0],
+			ASCIIName:      parts[1],
+			AlternateNames: alternateNames,
+			CountryCode:    parts[3],
+			Latitude:       mustParseFloat(parts[4], i),
+			Longitude:      mustParseFloat(parts[5], i),
+			Elevation:      mustParseFloat(parts[6], i),
+			Population:     int(mustParseFloat(parts[7], i)),
+			Timezone:       parts[8],
+		}
+
+		// Prepare the normalized names for searching
+		var keys []string
+		seen := map[string]struct{}{}
+		for _, name := range append([]string{c.Name, c.ASCIIName}, c.AlternateNames...) {
+			key := normalize(name)
+			if _, exist := seen[key]; key != "" && !exist {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+
+		cities = append(cities, indexedCity{City: c, keys: keys})
+	}
+}
+
+func mustParseFloat(s string, line int) float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		panic(fmt.Sprintf("city: invalid number at line %d: %v", line+1, err))
+	}
+	return f
+}
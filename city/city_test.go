@@ -0,0 +1,78 @@
+package city_test
+
+import (
+	"testing"
+
+	"github.com/hablullah/go-prayer/city"
+)
+
+func TestLookup(t *testing.T) {
+	testLookup(t, "Tromsø", "Tromsø", "NO")
+	testLookup(t, "tromso", "Tromsø", "NO")
+	testLookup(t, "TROMSØ", "Tromsø", "NO")
+	testLookup(t, "Makkah", "Mecca", "SA")
+	testLookup(t, "Ujung Pandang", "Makassar", "ID")
+	testLookup(t, "ndjamena", "N'Djamena", "TD")
+	testLookup(t, "Köln", "Cologne", "DE")
+	testLookup(t, "Hyderabad", "Hyderabad", "IN")
+
+	if _, exist := city.Lookup("Jakrta"); exist {
+		t.Errorf("lookup should only accept exact name")
+	}
+}
+
+func testLookup(t *testing.T, query string, name string, countryCode string) {
+	c, exist := city.Lookup(query)
+	if !exist {
+		t.Errorf("lookup %q: city not found", query)
+		return
+	}
+
+	if c.Name != name || c.CountryCode != countryCode {
+		t.Errorf("lookup %q: want %s (%s) got %s (%s)", query, name, countryCode, c.Name, c.CountryCode)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	testSearch(t, "Jakrta", "Jakarta")        // typo
+	testSearch(t, "Kuala", "Kuala Lumpur")    // prefix
+	testSearch(t, "Lumpur", "Kuala Lumpur")   // word
+	testSearch(t, "Tromos", "Tromsø")         // typo
+	testSearch(t, "Santiago, CL", "Santiago") // country filter
+	testSearch(t, "Sao Paolo", "Sao Paulo")   // typo in multiple words
+	testSearch(t, "Ho Chi Minh", "Ho Chi Minh City")
+
+	if results := city.Search("xyzzyq", 0); len(results) != 0 {
+		t.Errorf("search %q: want no result got %d", "xyzzyq", len(results))
+	}
+
+	if results := city.Search("a", 3); len(results) != 3 {
+		t.Errorf("search %q: want 3 results got %d", "a", len(results))
+	}
+}
+
+func testSearch(t *testing.T, query string, name string) {
+	results := city.Search(query, 5)
+	if len(results) == 0 {
+		t.Errorf("search %q: no result", query)
+		return
+	}
+
+	if results[0].Name != name {
+		t.Errorf("search %q: want %s got %s", query, name, results[0].Name)
+	}
+}
+
+func TestCities(t *testing.T) {
+	for _, c := range city.All() {
+		cfg, err := c.Config()
+		if err != nil {
+			t.Errorf("city %s has invalid timezone: %v", c.Name, err)
+			continue
+		}
+
+		if cfg.Latitude < -90 || cfg.Latitude > 90 || cfg.Longitude < -180 || cfg.Longitude > 180 {
+			t.Errorf("city %s has invalid coordinate", c.Name)
+		}
+	}
+}
@@ -4,4 +4,7 @@ go 1.20
 
 require github.com/hablullah/go-juliandays v1.0.1-0.20220316153050-f56193695a5b // indirect
 
-require github.com/hablullah/go-sampa v1.0.0
+require (
+	github.com/hablullah/go-sampa v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -6,7 +6,7 @@ import (
 	"github.com/hablullah/go-prayer"
 )
 
-var tzTromso, _ = time.LoadLocation("CET")
+var tzTromso, _ = time.LoadLocation("Europe/Oslo")
 
 var Tromso = TestData{
 	Name:      "Tromso",
@@ -0,0 +1,183 @@
+// Command city-gen converts the GeoNames cities dump (cities500.txt from
+// https://download.geonames.org/export/dump/) into the city list that embedded in
+// the `city` package. The list contains the cities above the population threshold,
+// plus the cities in the include list regardless of their population.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	src := flag.String("src", "cities500.txt", "path to GeoNames cities dump")
+	dst := flag.String("dst", "city/cities.tsv", "path to the generated city list")
+	include := flag.String("include", "scripts/city-gen/include.txt", "path to list of cities included regardless of population")
+	minPopulation := flag.Int("min-population", 500000, "minimum population of the city")
+	maxAlternateNames := flag.Int("max-alternate-names", 8, "maximum alternate names for each city")
+	flag.Parse()
+
+	includes, err := readIncludes(*include)
+	checkError(err)
+
+	err = generate(*src, *dst, includes, *minPopulation, *maxAlternateNames)
+	checkError(err)
+}
+
+func generate(src, dst string, includes map[string]struct{}, minPopulation, maxAlternateNames int) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	// Collect the cities first, since the included city is only the most populated
+	// one among the cities with the same name in a country.
+	var rows [][]string
+	includedIdx := map[string]int{}
+	scanner := bufio.NewScanner(srcFile)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		// Columns in GeoNames dump:
+		// 0 geonameid, 1 name, 2 asciiname, 3 alternatenames, 4 latitude, 5 longitude,
+		// 6 feature class, 7 feature code, 8 country code, 9 cc2, 10-13 admin codes,
+		// 14 population, 15 elevation, 16 dem, 17 timezone, 18 modification date
+		parts := strings.Split(scanner.Text(), "\t")
+		if len(parts) < 19 {
+			continue
+		}
+
+		population, _ := strconv.Atoi(parts[14])
+		if population >= minPopulation {
+			rows = append(rows, parts)
+			continue
+		}
+
+		for _, name := range []string{parts[1], parts[2]} {
+			key := includeKey(parts[8], name)
+			if _, included := includes[key]; !included {
+				continue
+			}
+
+			if idx, exist := includedIdx[key]; exist {
+				prevPopulation, _ := strconv.Atoi(rows[idx][14])
+				if population > prevPopulation {
+					rows[idx] = parts
+				}
+			} else {
+				includedIdx[key] = len(rows)
+				rows = append(rows, parts)
+			}
+			break
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	for key := range includes {
+		if _, exist := includedIdx[key]; !exist {
+			fmt.Fprintf(os.Stderr, "included city %q is not found\n", key)
+		}
+	}
+
+	dstFile, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	w := bufio.NewWriter(dstFile)
+	fmt.Fprintln(w, "# name\tascii_name\talternate_names\tcountry\tlatitude\tlongitude\televation\tpopulation\ttimezone")
+
+	for _, parts := range rows {
+		// Use elevation if exist, else use digital elevation model
+		elevation := parts[15]
+		if elevation == "" {
+			elevation = parts[16]
+		}
+		if elevation == "" || elevation == "-9999" {
+			elevation = "0"
+		}
+
+		population, _ := strconv.Atoi(parts[14])
+		alternateNames := latinNames(parts[3], parts[1], maxAlternateNames)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			parts[1], parts[2], strings.Join(alternateNames, ","), parts[8],
+			parts[4], parts[5], elevation, population, parts[17])
+	}
+
+	return w.Flush()
+}
+
+// readIncludes reads the list of cities that included regardless of population.
+// Each line contains the country code and the city name, separated by tab.
+func readIncludes(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	includes := map[string]struct{}{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		country, name, found := strings.Cut(line, "\t")
+		if !found {
+			return nil, fmt.Errorf("invalid include line: %q", line)
+		}
+		includes[includeKey(country, name)] = struct{}{}
+	}
+
+	return includes, scanner.Err()
+}
+
+func includeKey(country, name string) string {
+	return strings.ToUpper(country) + "/" + strings.ToLower(strings.TrimSpace(name))
+}
+
+// latinNames returns the alternate names that written in Latin script, since the
+// others can't be typed by most users anyway.
+func latinNames(alternateNames string, name string, limit int) []string {
+	var names []string
+	seen := map[string]struct{}{strings.ToLower(name): {}}
+	for _, alt := range strings.Split(alternateNames, ",") {
+		key := strings.ToLower(alt)
+		if _, exist := seen[key]; exist || alt == "" || !isLatin(alt) {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		names = append(names, alt)
+		if len(names) >= limit {
+			break
+		}
+	}
+	return names
+}
+
+func isLatin(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkError(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
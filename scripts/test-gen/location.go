@@ -1,9 +1,11 @@
 package main
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/hablullah/go-prayer"
+	"github.com/hablullah/go-prayer/city"
 )
 
 type Location struct {
@@ -14,27 +16,24 @@ type Location struct {
 }
 
 var testLocations = []Location{
-	{ // Tromso (Norway) is representation for location in North Frigid area
-		Name:      "Tromso",
-		Timezone:  "CET",
-		Latitude:  69.682778,
-		Longitude: 18.942778,
-	}, { // London (UK) is representation for location in North Temperate area
-		Name:      "London",
-		Timezone:  "Europe/London",
-		Latitude:  51.507222,
-		Longitude: -0.1275,
-	}, { // Jakarta (Indonesia) is representation for location in Torrid area
-		Name:      "Jakarta",
-		Timezone:  "Asia/Jakarta",
-		Latitude:  -6.175,
-		Longitude: 106.825,
-	}, { // Wellington (New Zealand) is representation for location in South Temperate area
-		Name:      "Wellington",
-		Timezone:  "Pacific/Auckland",
-		Latitude:  -41.288889,
-		Longitude: 174.777222,
-	},
+	fromCity("Tromsø"),     // North Frigid
+	fromCity("London"),     // North Temperate
+	fromCity("Jakarta"),    // Torrid
+	fromCity("Wellington"), // South Temperate
+}
+
+func fromCity(name string) Location {
+	c, exist := city.Lookup(name)
+	if !exist {
+		panic(fmt.Sprintf("city %s is not found", name))
+	}
+
+	return Location{
+		Name:      c.ASCIIName,
+		Timezone:  c.Timezone,
+		Latitude:  c.Latitude,
+		Longitude: c.Longitude,
+	}
 }
 
 func getSchedules(loc Location) []prayer.Schedule {
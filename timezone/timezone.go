@@ -0,0 +1,150 @@
+// Package timezone resolves the IANA time zone of a coordinate without network
+// access, using embedded and simplified time zone boundaries.
+//
+// The boundaries are approximated by a set of reference points, where each point is
+// located within a known time zone. The time zone of a coordinate is the time zone of
+// its nearest reference point, so the result might be wrong for locations that near
+// the border of two time zones. Since there are no polygons, the border is effectively
+// the midpoint between two reference points of different time zones. The reference
+// points include several border towns (e.g. Haparanda and Tornio, Narva and Ivangorod,
+// El Paso and Ciudad Juárez, and the West Bank cities that use Asia/Hebron), however
+// the locations between them might still be resolved into the neighboring time zone.
+// For location that far from every reference point,
+// e.g. in the middle of ocean, it will use the nautical time zone (Etc/GMT±N) based on
+// the longitude.
+//
+// This package also imports "time/tzdata", so the time zones can be loaded even in
+// system without "/usr/share/zoneinfo", e.g. in minimal container.
+package timezone
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata"
+)
+
+// maxDistance is the maximum distance in km between a coordinate and its nearest
+// reference point. If it's farther than this, the nautical time zone will be used.
+const maxDistance = 1000
+
+// earthRadius is the mean radius of Earth in km.
+const earthRadius = 6371.0
+
+//go:embed zones.csv
+var zonesCSV string
+
+type referencePoint struct {
+	Latitude  float64
+	Longitude float64
+	Name      string
+}
+
+var (
+	loadOnce        sync.Once
+	referencePoints []referencePoint
+)
+
+// Lookup returns the time zone for the specified coordinate. Its signature matches
+// `Config.TimezoneLookup` in the prayer package, so it can be used as the automatic
+// time zone resolver.
+func Lookup(latitude, longitude float64) (*time.Location, error) {
+	name, err := LookupName(latitude, longitude)
+	if err != nil {
+		return nil, err
+	}
+	return time.LoadLocation(name)
+}
+
+// LookupName returns the IANA name of the time zone for the specified coordinate.
+func LookupName(latitude, longitude float64) (string, error) {
+	if math.IsNaN(latitude) || latitude < -90 || latitude > 90 {
+		return "", fmt.Errorf("invalid latitude %v", latitude)
+	}
+
+	if math.IsNaN(longitude) || longitude < -180 || longitude > 180 {
+		return "", fmt.Errorf("invalid longitude %v", longitude)
+	}
+
+	loadOnce.Do(loadReferencePoints)
+
+	// Find the nearest reference point
+	nearestName, nearestDistance := "", math.MaxFloat64
+	for _, p := range referencePoints {
+		distance := haversine(latitude, longitude, p.Latitude, p.Longitude)
+		if distance < nearestDistance {
+			nearestName, nearestDistance = p.Name, distance
+		}
+	}
+
+	if nearestDistance <= maxDistance {
+		return nearestName, nil
+	}
+
+	return nauticalName(longitude), nil
+}
+
+// nauticalName returns the name of nautical time zone for the longitude. Note that
+// the sign in "Etc/GMT±N" is inverted, e.g. "Etc/GMT-7" is UTC+7.
+func nauticalName(longitude float64) string {
+	offset := int(math.Round(longitude / 15))
+	switch {
+	case offset > 0:
+		return fmt.Sprintf("Etc/GMT-%d", offset)
+	case offset < 0:
+		return fmt.Sprintf("Etc/GMT+%d", -offset)
+	default:
+		return "Etc/GMT"
+	}
+}
+
+// haversine returns the great circle distance in km between two coordinates.
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	lat1, lon1 = degToRad(lat1), degToRad(lon1)
+	lat2, lon2 = degToRad(lat2), degToRad(lon2)
+
+	sinDLat := math.Sin((lat2 - lat1) / 2)
+	sinDLon := math.Sin((lon2 - lon1) / 2)
+	a := sinDLat*sinDLat + math.Cos(lat1)*math.Cos(lat2)*sinDLon*sinDLon
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func degToRad(d float64) float64 {
+	return d * math.Pi / 180
+}
+
+// loadReferencePoints parses the embedded reference points. Since the data is
+// embedded at compile time, invalid data is a programming error so it will panic.
+func loadReferencePoints() {
+	for i, line := range strings.Split(zonesCSV, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.Split(line, ",")
+		if len(parts) != 3 {
+			panic(fmt.Sprintf("timezone: invalid reference point at line %d", i+1))
+		}
+
+		latitude, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			panic(fmt.Sprintf("timezone: invalid latitude at line %d: %v", i+1, err))
+		}
+
+		longitude, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			panic(fmt.Sprintf("timezone: invalid longitude at line %d: %v", i+1, err))
+		}
+
+		referencePoints = append(referencePoints, referencePoint{
+			Latitude:  latitude,
+			Longitude: longitude,
+			Name:      parts[2],
+		})
+	}
+}
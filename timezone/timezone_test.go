@@ -0,0 +1,115 @@
+package timezone_test
+
+import (
+	"testing"
+
+	"github.com/hablullah/go-prayer"
+	"github.com/hablullah/go-prayer/timezone"
+)
+
+func TestLookupName(t *testing.T) {
+	testLookupName(t, "Jakarta", -6.2, 106.816667, "Asia/Jakarta")
+	testLookupName(t, "Makassar", -5.133333, 119.416667, "Asia/Makassar")
+	testLookupName(t, "London", 51.507222, -0.1275, "Europe/London")
+	testLookupName(t, "Tromso", 69.682778, 18.942778, "Europe/Oslo")
+	testLookupName(t, "Wellington", -41.288889, 174.777222, "Pacific/Auckland")
+	testLookupName(t, "New York", 40.7, -74, "America/New_York")
+	testLookupName(t, "Los Angeles", 34.05, -118.25, "America/Los_Angeles")
+	testLookupName(t, "Mecca", 21.4225, 39.826111, "Asia/Riyadh")
+	testLookupName(t, "South Atlantic", -30, -20, "Etc/GMT+1")
+	testLookupName(t, "North Pacific", 30, -150, "Etc/GMT+10")
+}
+
+func TestLookupBorder(t *testing.T) {
+	// West Bank uses Asia/Hebron, while Jerusalem and Gaza have their own zone
+	testLookupName(t, "Jerusalem", 31.778, 35.225, "Asia/Jerusalem")
+	testLookupName(t, "Ramallah", 31.9, 35.2, "Asia/Hebron")
+	testLookupName(t, "Bethlehem", 31.7, 35.2, "Asia/Hebron")
+	testLookupName(t, "Hebron", 31.53, 35.09, "Asia/Hebron")
+	testLookupName(t, "Nablus", 32.22, 35.26, "Asia/Hebron")
+	testLookupName(t, "Jericho", 31.86, 35.44, "Asia/Hebron")
+	testLookupName(t, "Jenin", 32.46, 35.3, "Asia/Hebron")
+	testLookupName(t, "Tulkarm", 32.31, 35.03, "Asia/Hebron")
+	testLookupName(t, "Qalqilya", 32.19, 34.97, "Asia/Hebron")
+	testLookupName(t, "Tel Aviv", 32.08, 34.78, "Asia/Jerusalem")
+	testLookupName(t, "Beersheba", 31.25, 34.79, "Asia/Jerusalem")
+	testLookupName(t, "Khan Yunis", 31.34, 34.31, "Asia/Gaza")
+	testLookupName(t, "Eilat", 29.557, 34.952, "Asia/Jerusalem")
+	testLookupName(t, "Aqaba", 29.53, 35.0, "Asia/Amman")
+
+	// Neighboring cities across a time zone border
+	testLookupName(t, "Haparanda", 65.835, 24.137, "Europe/Stockholm")
+	testLookupName(t, "Tornio", 65.848, 24.146, "Europe/Helsinki")
+	testLookupName(t, "Narva", 59.377, 28.19, "Europe/Tallinn")
+	testLookupName(t, "Ivangorod", 59.37, 28.22, "Europe/Moscow")
+	testLookupName(t, "El Paso", 31.76, -106.49, "America/Denver")
+	testLookupName(t, "Ciudad Juarez", 31.69, -106.42, "America/Ciudad_Juarez")
+	testLookupName(t, "Blagoveshchensk", 50.27, 127.53, "Asia/Yakutsk")
+	testLookupName(t, "Heihe", 50.24, 127.49, "Asia/Shanghai")
+	testLookupName(t, "Detroit", 42.33, -83.05, "America/Detroit")
+	testLookupName(t, "Windsor", 42.31, -83.03, "America/Toronto")
+	testLookupName(t, "Gibraltar", 36.14, -5.35, "Europe/Gibraltar")
+	testLookupName(t, "La Linea", 36.165, -5.348, "Europe/Madrid")
+	testLookupName(t, "Strasbourg", 48.58, 7.75, "Europe/Paris")
+	testLookupName(t, "Kehl", 48.57, 7.81, "Europe/Berlin")
+	testLookupName(t, "Tabatinga", -4.25, -69.94, "America/Manaus")
+	testLookupName(t, "Leticia", -4.21, -69.94, "America/Bogota")
+	testLookupName(t, "Pensacola", 30.42, -87.22, "America/Chicago")
+	testLookupName(t, "Tallahassee", 30.44, -84.28, "America/New_York")
+}
+
+func testLookupName(t *testing.T, name string, latitude, longitude float64, expected string) {
+	result, err := timezone.LookupName(latitude, longitude)
+	if err != nil {
+		t.Errorf("lookup %s has error: %v", name, err)
+		return
+	}
+
+	if result != expected {
+		t.Errorf("lookup %s: want %q got %q", name, expected, result)
+	}
+}
+
+func TestLookupInvalid(t *testing.T) {
+	for _, c := range [][2]float64{{91, 0}, {-91, 0}, {0, 181}, {0, -181}} {
+		if _, err := timezone.Lookup(c[0], c[1]); err == nil {
+			t.Errorf("lookup %v should be rejected", c)
+		}
+	}
+}
+
+func TestLookupAllZones(t *testing.T) {
+	// Every embedded zone must be loadable, which also covers the tzdata fallback
+	for lat := -90.0; lat <= 90; lat += 5 {
+		for lon := -180.0; lon <= 180; lon += 5 {
+			if _, err := timezone.Lookup(lat, lon); err != nil {
+				t.Fatalf("lookup (%v, %v) has error: %v", lat, lon, err)
+			}
+		}
+	}
+}
+
+func TestAutoTimezone(t *testing.T) {
+	schedules, err := prayer.Calculate(prayer.Config{
+		Latitude:       -6.2,
+		Longitude:      106.816667,
+		TimezoneLookup: timezone.Lookup,
+	}, 2023)
+	if err != nil {
+		t.Fatalf("calculate has error: %v", err)
+	}
+
+	zone := schedules[0].Zuhr.Location().String()
+	if zone != "Asia/Jakarta" {
+		t.Errorf("auto timezone: want %q got %q", "Asia/Jakarta", zone)
+	}
+
+	// Lookup error is returned by Calculate
+	_, err = prayer.Calculate(prayer.Config{
+		Latitude:       100,
+		TimezoneLookup: timezone.Lookup,
+	}, 2023)
+	if err == nil {
+		t.Errorf("invalid latitude should be rejected")
+	}
+}